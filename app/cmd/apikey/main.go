@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/service"
+	"github.com/hamfa/task-manager/internal/tenant"
+	"github.com/hamfa/task-manager/pkg/config"
+)
+
+const usage = `Usage: apikey <command> [flags]
+
+Commands:
+  create -name NAME [-scopes S1,S2] [-ttl DURATION] [-tenant TENANT]
+                     Create a key and print it (shown only once)
+  list               List the keys of every tenant
+  revoke -id ID      Revoke a key
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer func() { _ = logger.Sync() }()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("failed to load config", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.PostgresDSN())
+	if err != nil {
+		logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
+	}
+	defer pool.Close()
+
+	keys := service.NewAPIKeyService(repository.NewPostgresAPIKeyRepository(pool), logger)
+
+	// The CLI administers the whole deployment rather than one tenant
+	adminCtx := tenant.WithID(ctx, tenant.All)
+
+	switch os.Args[1] {
+	case "create":
+		fs := flag.NewFlagSet("create", flag.ExitOnError)
+		name := fs.String("name", "", "name describing the client")
+		scopes := fs.String("scopes", model.ScopeTasksRead, "comma-separated scopes: tasks:read, tasks:write, admin")
+		ttl := fs.Duration("ttl", 0, "lifetime of the key (default: never expires)")
+		tenantID := fs.String("tenant", "", "tenant to bind the key to (default: chosen per request)")
+		_ = fs.Parse(os.Args[2:])
+
+		req := model.APIKeyCreateRequest{Name: *name, Scopes: strings.Split(*scopes, ",")}
+		if *ttl > 0 {
+			expiresAt := time.Now().Add(*ttl)
+			req.ExpiresAt = &expiresAt
+		}
+		if req.Name == "" {
+			logger.Fatal("-name is required")
+		}
+		createCtx := adminCtx
+		if *tenantID != "" {
+			if !tenant.Valid(*tenantID) {
+				logger.Fatal("invalid -tenant", zap.String("tenant", *tenantID))
+			}
+			createCtx = tenant.WithID(ctx, *tenantID)
+		}
+
+		key, raw, err := keys.Create(createCtx, req)
+		if err != nil {
+			logger.Fatal("failed to create api key", zap.Error(err))
+		}
+		fmt.Printf("created %s (%s) with scopes %s\n\n%s\n",
+			key.ID, key.Prefix, strings.Join(key.Scopes, ","), raw)
+
+	case "list":
+		list, err := keys.List(adminCtx)
+		if err != nil {
+			logger.Fatal("failed to list api keys", zap.Error(err))
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tPREFIX\tNAME\tSCOPES\tTENANT\tSTATUS\tLAST USED")
+		for _, k := range list {
+			state := "active"
+			if !k.Active(time.Now()) {
+				state = "inactive"
+			}
+			lastUsed := "-"
+			if k.LastUsedAt != nil {
+				lastUsed = k.LastUsedAt.Format(time.RFC3339)
+			}
+			keyTenant := k.TenantID
+			if keyTenant == "" {
+				keyTenant = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				k.ID, k.Prefix, k.Name, strings.Join(k.Scopes, ","), keyTenant, state, lastUsed)
+		}
+		_ = w.Flush()
+
+	case "revoke":
+		fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+		id := fs.String("id", "", "ID of the key to revoke")
+		_ = fs.Parse(os.Args[2:])
+
+		if _, err := keys.Revoke(adminCtx, *id); err != nil {
+			logger.Fatal("failed to revoke api key", zap.Error(err))
+		}
+		fmt.Printf("revoked %s\n", *id)
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/migrate"
+	"github.com/hamfa/task-manager/migrations"
+	"github.com/hamfa/task-manager/pkg/config"
+)
+
+const usage = `Usage: migrate <command> [flags]
+
+Commands:
+  up                 Apply all pending migrations
+  down [-steps N]    Roll back the last N applied migrations (default 1)
+  status             Show applied and pending migrations
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	logger, _ := zap.NewProduction()
+	defer func() { _ = logger.Sync() }()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatal("failed to load config", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, cfg.PostgresDSN())
+	if err != nil {
+		logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
+	}
+	defer pool.Close()
+
+	migrator, err := migrate.New(pool, migrations.FS, logger)
+	if err != nil {
+		logger.Fatal("failed to load migrations", zap.Error(err))
+	}
+
+	switch os.Args[1] {
+	case "up":
+		n, err := migrator.Up(ctx)
+		if err != nil {
+			logger.Fatal("migration failed", zap.Error(err))
+		}
+		fmt.Printf("applied %d migration(s)\n", n)
+
+	case "down":
+		fs := flag.NewFlagSet("down", flag.ExitOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		_ = fs.Parse(os.Args[2:])
+
+		n, err := migrator.Down(ctx, *steps)
+		if err != nil {
+			logger.Fatal("rollback failed", zap.Error(err))
+		}
+		fmt.Printf("rolled back %d migration(s)\n", n)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			logger.Fatal("failed to read migration status", zap.Error(err))
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, st := range statuses {
+			state, appliedAt := "pending", "-"
+			if st.Applied {
+				state, appliedAt = "applied", st.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+		}
+		_ = w.Flush()
+
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
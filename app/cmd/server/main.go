@@ -11,16 +11,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.uber.org/zap"
 
+	"github.com/hamfa/task-manager/internal/auth"
 	"github.com/hamfa/task-manager/internal/handler"
+	"github.com/hamfa/task-manager/internal/metrics"
 	"github.com/hamfa/task-manager/internal/middleware"
+	"github.com/hamfa/task-manager/internal/migrate"
 	"github.com/hamfa/task-manager/internal/model"
 	"github.com/hamfa/task-manager/internal/repository"
 	"github.com/hamfa/task-manager/internal/service"
+	"github.com/hamfa/task-manager/internal/tenant"
+	"github.com/hamfa/task-manager/migrations"
 	"github.com/hamfa/task-manager/pkg/config"
 )
 
@@ -40,48 +49,178 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// ── Connect to PostgreSQL ──────────────────────────────────────
-	pgPool, err := pgxpool.New(ctx, cfg.PostgresDSN())
-	if err != nil {
-		logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
+	var (
+		taskStore     repository.TaskStore
+		userStore     repository.UserStore
+		projectStore  repository.ProjectStore
+		depStore      repository.DependencyStore
+		apiKeyStore   repository.APIKeyStore
+		activityStore repository.ActivityStore
+		commentStore  repository.CommentStore
+		attachStore   repository.AttachmentStore
+		taskCache     repository.TaskCache
+		summaryCache  repository.ProjectSummaryCache
+		healthChecks  map[string]func(context.Context) error
+	)
+
+	if cfg.StorageBackend == config.StorageMemory {
+		// ── In-Memory Storage ──────────────────────────────────────────
+		memoryTasks := repository.NewMemoryTaskStore()
+		memoryCache := repository.NewMemoryCache()
+		taskStore = memoryTasks
+		userStore = repository.NewMemoryUserStore()
+		projectStore = repository.NewMemoryProjectStore(memoryTasks)
+		depStore = repository.NewMemoryDependencyStore(memoryTasks)
+		apiKeyStore = repository.NewMemoryAPIKeyStore()
+		activityStore = repository.NewMemoryActivityStore()
+		commentStore = repository.NewMemoryCommentStore()
+		attachStore = repository.NewMemoryAttachmentStore(memoryTasks)
+		taskCache = memoryCache
+		summaryCache = memoryCache
+		healthChecks = map[string]func(context.Context) error{
+			"memory": taskStore.Ping,
+		}
+		logger.Warn("using in-memory storage; data will not survive a restart")
+	} else {
+		// ── Connect to PostgreSQL ──────────────────────────────────────
+		pgConfig, err := pgxpool.ParseConfig(cfg.PostgresDSN())
+		if err != nil {
+			logger.Fatal("invalid PostgreSQL configuration", zap.Error(err))
+		}
+		if cfg.PostgresRLS {
+			pgConfig.BeforeAcquire = repository.SetTenantSetting
+		}
+		pgPool, err := pgxpool.NewWithConfig(ctx, pgConfig)
+		if err != nil {
+			logger.Fatal("failed to connect to PostgreSQL", zap.Error(err))
+		}
+		defer pgPool.Close()
+		logger.Info("connected to PostgreSQL")
+
+		// ── Connect to MongoDB ─────────────────────────────────────────
+		mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+		if err != nil {
+			logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+		}
+		defer func() { _ = mongoClient.Disconnect(ctx) }()
+		logger.Info("connected to MongoDB")
+
+		// ── Connect to Redis ───────────────────────────────────────────
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr(),
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+		})
+		defer redisClient.Close()
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			logger.Fatal("failed to connect to Redis", zap.Error(err))
+		}
+		logger.Info("connected to Redis")
+
+		// ── Register Pool Metrics ──────────────────────────────────────
+		prometheus.MustRegister(
+			metrics.NewPgxPoolCollector(pgPool),
+			metrics.NewRedisPoolCollector(redisClient),
+		)
+
+		// ── Initialize Repositories ────────────────────────────────────
+		postgresRepo := repository.NewPostgresRepository(pgPool)
+		mongoRepo := repository.NewMongoRepository(mongoClient.Database(cfg.MongoDB))
+		redisCache := repository.NewRedisCache(redisClient)
+
+		// Apply pending schema migrations
+		if cfg.MigrateOnStart {
+			migrator, err := migrate.New(pgPool, migrations.FS, logger)
+			if err != nil {
+				logger.Fatal("failed to load migrations", zap.Error(err))
+			}
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				logger.Fatal("failed to migrate schema", zap.Error(err))
+			}
+			logger.Info("database schema migrated", zap.Int("applied", applied))
+
+			// Activity logs predating tenants follow their tasks into the default tenant
+			backfilled, err := mongoRepo.BackfillTenant(ctx, tenant.Default)
+			if err != nil {
+				logger.Fatal("failed to backfill activity tenants", zap.Error(err))
+			}
+			if backfilled > 0 {
+				logger.Info("activity logs assigned to the default tenant", zap.Int64("count", backfilled))
+			}
+		}
+
+		taskStore = postgresRepo
+		userStore = repository.NewPostgresUserRepository(pgPool)
+		projectStore = repository.NewPostgresProjectRepository(pgPool)
+		depStore = repository.NewPostgresDependencyRepository(pgPool)
+		attachStore = repository.NewPostgresAttachmentRepository(pgPool)
+		apiKeyStore = repository.NewPostgresAPIKeyRepository(pgPool)
+		activityStore = mongoRepo
+		commentStore = mongoRepo
+		taskCache = redisCache
+		summaryCache = redisCache
+		healthChecks = map[string]func(context.Context) error{
+			"postgresql": postgresRepo.Ping,
+			"mongodb":    mongoRepo.Ping,
+			"redis":      redisCache.Ping,
+		}
+	}
+
+	// ── Attachment Contents ────────────────────────────────────────
+	var blobStore repository.BlobStore
+	if cfg.AttachmentStore == config.BlobStoreS3 {
+		minioClient, err := minio.New(cfg.S3Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+			Secure: cfg.S3UseSSL,
+			Region: cfg.S3Region,
+		})
+		if err != nil {
+			logger.Fatal("invalid S3 configuration", zap.Error(err))
+		}
+		s3Store := repository.NewS3BlobStore(minioClient, cfg.S3Bucket)
+		if err := s3Store.EnsureBucket(ctx, cfg.S3Region); err != nil {
+			logger.Fatal("failed to prepare S3 bucket", zap.Error(err))
+		}
+		logger.Info("storing attachments in S3", zap.String("bucket", cfg.S3Bucket))
+		blobStore = s3Store
+	} else {
+		localStore, err := repository.NewLocalBlobStore(cfg.AttachmentDir)
+		if err != nil {
+			logger.Fatal("failed to prepare attachment directory", zap.Error(err))
+		}
+		blobStore = localStore
 	}
-	defer pgPool.Close()
-	logger.Info("connected to PostgreSQL")
+	healthChecks["attachments"] = blobStore.Ping
 
-	// ── Connect to MongoDB ─────────────────────────────────────────
-	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoURI))
+	// ── Initialize Service & Handlers ──────────────────────────────
+	workflow, err := model.ParseWorkflow(cfg.TaskWorkflow)
 	if err != nil {
-		logger.Fatal("failed to connect to MongoDB", zap.Error(err))
+		logger.Fatal("invalid TASK_WORKFLOW", zap.Error(err))
 	}
-	defer func() { _ = mongoClient.Disconnect(ctx) }()
-	logger.Info("connected to MongoDB")
-
-	// ── Connect to Redis ───────────────────────────────────────────
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr(),
-		Password: cfg.RedisPassword,
-		DB:       cfg.RedisDB,
-	})
-	defer redisClient.Close()
-	if err := redisClient.Ping(ctx).Err(); err != nil {
-		logger.Fatal("failed to connect to Redis", zap.Error(err))
+	subtaskRules := model.SubtaskRules{
+		CompleteWithOpenSubtasks: cfg.SubtaskCompleteWithOpen,
+		OnDelete:                 cfg.SubtaskOnDelete,
 	}
-	logger.Info("connected to Redis")
-
-	// ── Initialize Repositories ────────────────────────────────────
-	postgresRepo := repository.NewPostgresRepository(pgPool)
-	mongoRepo := repository.NewMongoRepository(mongoClient.Database(cfg.MongoDB))
-	redisCache := repository.NewRedisCache(redisClient)
-
-	// Initialize database schema
-	if err := postgresRepo.InitSchema(ctx); err != nil {
-		logger.Fatal("failed to initialize schema", zap.Error(err))
+	if err := subtaskRules.Validate(); err != nil {
+		logger.Fatal("invalid SUBTASK_ON_DELETE", zap.Error(err))
+	}
+	if !tenant.Valid(cfg.DefaultTenant) {
+		logger.Fatal("invalid DEFAULT_TENANT", zap.String("tenant", cfg.DefaultTenant))
 	}
-	logger.Info("database schema initialized")
 
-	// ── Initialize Service & Handlers ──────────────────────────────
-	taskService := service.NewTaskService(postgresRepo, mongoRepo, redisCache, logger)
+	taskService := service.NewTaskService(taskStore, userStore, projectStore, depStore, activityStore, blobStore, taskCache, summaryCache, workflow, subtaskRules, logger)
 	taskHandler := handler.NewTaskHandler(taskService)
+	userService := service.NewUserService(userStore, projectStore, taskService, logger)
+	userHandler := handler.NewUserHandler(userService)
+	projectService := service.NewProjectService(projectStore, userStore, summaryCache, logger)
+	projectHandler := handler.NewProjectHandler(projectService)
+	commentService := service.NewCommentService(commentStore, taskService, logger)
+	commentHandler := handler.NewCommentHandler(commentService)
+	attachmentService := service.NewAttachmentService(attachStore, blobStore, taskService, cfg.AttachmentMaxBytes, logger)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService, cfg.AttachmentTransferTimeout)
+	apiKeyService := service.NewAPIKeyService(apiKeyStore, logger)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 
 	// ── Setup Gin Router ───────────────────────────────────────────
 	if cfg.Environment == "production" {
@@ -90,32 +229,19 @@ func main() {
 
 	router := gin.New()
 	router.Use(middleware.Logger(logger))
+	router.Use(middleware.Metrics())
 	router.Use(middleware.Recovery(logger))
 	router.Use(gin.Recovery())
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		services := map[string]string{}
-
-		// Check PostgreSQL
-		if err := postgresRepo.Ping(c.Request.Context()); err != nil {
-			services["postgresql"] = "unhealthy"
-		} else {
-			services["postgresql"] = "healthy"
-		}
-
-		// Check MongoDB
-		if err := mongoRepo.Ping(c.Request.Context()); err != nil {
-			services["mongodb"] = "unhealthy"
-		} else {
-			services["mongodb"] = "healthy"
-		}
-
-		// Check Redis
-		if err := redisCache.Ping(c.Request.Context()); err != nil {
-			services["redis"] = "unhealthy"
-		} else {
-			services["redis"] = "healthy"
+		for name, ping := range healthChecks {
+			if err := ping(c.Request.Context()); err != nil {
+				services[name] = "unhealthy"
+			} else {
+				services[name] = "healthy"
+			}
 		}
 
 		status := "healthy"
@@ -135,17 +261,65 @@ func main() {
 		})
 	})
 
-	// API routes
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// API routes; /health and /metrics stay public
 	api := router.Group("/api")
+	if cfg.AuthEnabled {
+		schemes := map[string]auth.Authenticator{"ApiKey": apiKeyService}
+
+		// Bearer tokens are accepted once any JWT key is configured
+		if cfg.JWTConfigured() {
+			verifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+				Issuer:           cfg.JWTIssuer,
+				Audience:         cfg.JWTAudience,
+				HS256Secret:      cfg.JWTHS256Secret,
+				RSAPublicKeyFile: cfg.JWTRSAPublicKeyFile,
+				JWKSFile:         cfg.JWTJWKSFile,
+				Leeway:           cfg.JWTLeeway,
+			})
+			if err != nil {
+				logger.Fatal("failed to configure JWT authentication", zap.Error(err))
+			}
+			schemes["Bearer"] = verifier
+		}
+
+		api.Use(middleware.Authenticate(schemes, logger))
+	} else {
+		logger.Warn("authentication disabled; /api routes are anonymous")
+		api.Use(middleware.Anonymous())
+	}
+	api.Use(middleware.Tenant(cfg.DefaultTenant))
 	taskHandler.RegisterRoutes(api)
+	userHandler.RegisterRoutes(api)
+	projectHandler.RegisterRoutes(api)
+	commentHandler.RegisterRoutes(api)
+	attachmentHandler.RegisterRoutes(api)
+	apiKeyHandler.RegisterRoutes(api)
+
+	// ── Start Background Jobs ──────────────────────────────────────
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.RemindersEnabled {
+		reminders := service.NewReminderScheduler(taskStore, activityStore,
+			cfg.ReminderInterval, cfg.DueSoonWindow, logger)
+		go reminders.Run(jobsCtx)
+	}
+	if cfg.TrashRetention > 0 {
+		purger := service.NewTrashPurger(taskStore, activityStore, blobStore,
+			cfg.TrashRetention, cfg.TrashPurgeInterval, logger)
+		go purger.Run(jobsCtx)
+	}
 
 	// ── Start Server with Graceful Shutdown ─────────────────────────
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.ServerPort),
 		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+		ReadTimeout:  cfg.ServerReadTimeout,
+		WriteTimeout: cfg.ServerWriteTimeout,
+		IdleTimeout:  cfg.ServerIdleTimeout,
 	}
 
 	// Start server in goroutine
@@ -161,6 +335,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logger.Info("shutting down server...")
+	stopJobs()
 
 	// Graceful shutdown with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
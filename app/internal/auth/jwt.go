@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// ErrUnauthenticated is returned for missing, malformed or rejected credentials
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// tokenClaims are the claims read from a bearer token
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	// Scope is a space-separated scope list (RFC 8693)
+	Scope string `json:"scope"`
+	// Role is one of RoleViewer, RoleMember and RoleAdmin
+	Role string `json:"role"`
+	// Tenant binds the token to one tenant
+	Tenant string `json:"tenant"`
+}
+
+// JWTConfig configures token verification. At least one of HS256Secret,
+// RSAPublicKeyFile and JWKSFile must be set.
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// HS256Secret enables HS256 tokens signed with this shared secret
+	HS256Secret string
+	// RSAPublicKeyFile enables RS256 tokens signed by this PEM-encoded key
+	RSAPublicKeyFile string
+	// JWKSFile enables RS256 tokens signed by any key in this JSON Web Key Set;
+	// tokens must name the key with a "kid" header
+	JWKSFile string
+	// Leeway tolerates clock skew when checking exp and nbf
+	Leeway time.Duration
+}
+
+// JWTVerifier validates bearer tokens
+type JWTVerifier struct {
+	parser  *jwt.Parser
+	secret  []byte
+	rsaKey  *rsa.PublicKey
+	jwks    map[string]*rsa.PublicKey
+	methods []string
+}
+
+// NewJWTVerifier loads the configured keys
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	v := &JWTVerifier{}
+
+	if cfg.HS256Secret != "" {
+		v.secret = []byte(cfg.HS256Secret)
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.RSAPublicKeyFile != "" {
+		pem, err := os.ReadFile(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read RSA public key: %w", err)
+		}
+		v.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA public key: %w", err)
+		}
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.jwks = keys
+	}
+
+	if v.rsaKey != nil || len(v.jwks) > 0 {
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(v.methods) == 0 {
+		return nil, errors.New("no JWT verification key configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Authenticate verifies a bearer token; it implements Authenticator
+func (v *JWTVerifier) Authenticate(_ context.Context, token string) (Principal, error) {
+	return v.Verify(token)
+}
+
+// Verify checks a token's signature, issuer, audience and expiry and returns
+// its principal
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	var claims tokenClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return Principal{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+	if claims.Subject == "" {
+		return Principal{}, fmt.Errorf("%w: token has no subject", ErrUnauthenticated)
+	}
+
+	role := claims.Role
+	if role == "" {
+		role = DefaultRole
+	}
+	if !ValidRole(role) {
+		return Principal{}, fmt.Errorf("%w: unknown role %q", ErrUnauthenticated, role)
+	}
+
+	if claims.Tenant != "" && !tenant.Valid(claims.Tenant) {
+		return Principal{}, fmt.Errorf("%w: invalid tenant %q", ErrUnauthenticated, claims.Tenant)
+	}
+
+	scopes := strings.Fields(claims.Scope)
+	if len(scopes) == 0 {
+		scopes = RoleScopes(role)
+	}
+
+	return Principal{
+		Subject: claims.Subject,
+		Method:  MethodJWT,
+		Role:    role,
+		Scopes:  scopes,
+		Tenant:  claims.Tenant,
+	}, nil
+}
+
+// key selects the verification key for a token; the parser has already
+// restricted the algorithm to one of v.methods
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return v.secret, nil
+
+	case jwt.SigningMethodRS256.Alg():
+		if kid, ok := token.Header["kid"].(string); ok && len(v.jwks) > 0 {
+			if key, ok := v.jwks[kid]; ok {
+				return key, nil
+			}
+			if v.rsaKey == nil {
+				return nil, fmt.Errorf("unknown key id %q", kid)
+			}
+		}
+		if v.rsaKey == nil {
+			return nil, errors.New("token has no key id")
+		}
+		return v.rsaKey, nil
+	}
+
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+// jsonWebKey is the subset of RFC 7517 needed for RSA signature keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// loadJWKS reads the RSA signing keys of a JSON Web Key Set, indexed by kid
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS: %w", err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %q: invalid modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %q: invalid exponent: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no RSA signing keys")
+	}
+
+	return keys, nil
+}
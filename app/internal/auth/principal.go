@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// Authentication methods recorded on a Principal
+const (
+	MethodJWT    = "jwt"
+	MethodAPIKey = "apikey"
+	// MethodAnonymous is used for every request when authentication is disabled
+	MethodAnonymous = "anonymous"
+)
+
+// SystemActor is the actor recorded for changes made by background jobs
+const SystemActor = "system"
+
+// Principal is the authenticated identity behind a request
+type Principal struct {
+	// Subject is the JWT "sub" claim, or "apikey:<prefix>" for API keys
+	Subject string
+	// Method is how the principal authenticated, e.g. MethodJWT
+	Method string
+	// Role decides which permissions the principal holds, e.g. RoleMember
+	Role string
+	// Scopes granted to the principal, e.g. model.ScopeTasksRead
+	Scopes []string
+	// Tenant the principal is bound to; "" leaves it to CanChooseTenant
+	Tenant string
+}
+
+// CanChooseTenant reports whether the principal may pick the tenant of each
+// request. Only admins that are not bound to a tenant may; other unbound
+// principals stay in the default tenant.
+func (p Principal) CanChooseTenant() bool {
+	return p.Tenant == "" && p.Role == RoleAdmin && p.HasScope(model.ScopeAdmin)
+}
+
+// Anonymous is the principal of every request when authentication is
+// disabled; it holds every permission
+var Anonymous = Principal{Method: MethodAnonymous, Role: RoleAdmin, Scopes: []string{model.ScopeAdmin}}
+
+// HasScope reports whether the principal was granted scope; model.ScopeAdmin
+// grants every scope
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == model.ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator turns the credentials of one Authorization scheme into a principal
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials string) (Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying p
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// Actor returns the subject to record as the actor of a change made with ctx,
+// or "" for unauthenticated requests
+func Actor(ctx context.Context) string {
+	p, _ := FromContext(ctx)
+	return p.Subject
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// Roles, from least to most privileged
+const (
+	RoleViewer = "viewer"
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+// DefaultRole is given to tokens without a "role" claim
+const DefaultRole = RoleMember
+
+// Permission names an operation guarded by role-based access control
+type Permission string
+
+// Permissions checked by the API routes
+const (
+	PermReadTasks      Permission = "tasks.read"
+	PermCreateTasks    Permission = "tasks.create"
+	PermUpdateTasks    Permission = "tasks.update"
+	PermDeleteTasks    Permission = "tasks.delete"
+	PermPurgeTasks     Permission = "tasks.purge"
+	PermReadActivities Permission = "activities.read"
+	PermFlushCache     Permission = "cache.flush"
+	PermReadUsers      Permission = "users.read"
+	PermManageUsers    Permission = "users.manage"
+	PermManageAPIKeys  Permission = "api_keys.manage"
+	PermReadProjects   Permission = "projects.read"
+	PermManageProjects Permission = "projects.manage"
+	PermDeleteProjects Permission = "projects.delete"
+	PermReadComments   Permission = "comments.read"
+	PermWriteComments  Permission = "comments.write"
+	// PermModerateComments allows editing and deleting other people's comments
+	PermModerateComments Permission = "comments.moderate"
+)
+
+// rolePermissions lists what each role may do. Purging a task or deleting a
+// project is permanent, so it is reserved for admins.
+var rolePermissions = map[string][]Permission{
+	RoleViewer: {PermReadTasks, PermReadUsers, PermReadProjects, PermReadComments},
+	RoleMember: {
+		PermReadTasks, PermReadUsers, PermReadProjects, PermReadComments,
+		PermCreateTasks, PermUpdateTasks, PermReadActivities, PermManageProjects, PermWriteComments,
+		PermDeleteTasks,
+	},
+	RoleAdmin: {
+		PermReadTasks, PermReadUsers, PermReadProjects, PermReadComments,
+		PermCreateTasks, PermUpdateTasks, PermReadActivities, PermManageProjects, PermWriteComments,
+		PermDeleteTasks, PermPurgeTasks, PermDeleteProjects, PermFlushCache, PermManageUsers, PermManageAPIKeys,
+		PermModerateComments,
+	},
+}
+
+// permissionScopes is the scope a credential needs for each permission, on
+// top of a role that grants it
+var permissionScopes = map[Permission]string{
+	PermReadTasks:        model.ScopeTasksRead,
+	PermReadUsers:        model.ScopeTasksRead,
+	PermReadActivities:   model.ScopeTasksRead,
+	PermReadProjects:     model.ScopeTasksRead,
+	PermReadComments:     model.ScopeTasksRead,
+	PermCreateTasks:      model.ScopeTasksWrite,
+	PermUpdateTasks:      model.ScopeTasksWrite,
+	PermDeleteTasks:      model.ScopeTasksWrite,
+	PermPurgeTasks:       model.ScopeTasksWrite,
+	PermManageProjects:   model.ScopeTasksWrite,
+	PermDeleteProjects:   model.ScopeTasksWrite,
+	PermWriteComments:    model.ScopeTasksWrite,
+	PermModerateComments: model.ScopeTasksWrite,
+	PermFlushCache:       model.ScopeAdmin,
+	PermManageUsers:      model.ScopeAdmin,
+	PermManageAPIKeys:    model.ScopeAdmin,
+}
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleScopes returns the scopes granted to a token for role when the token
+// does not list its own
+func RoleScopes(role string) []string {
+	switch role {
+	case RoleAdmin:
+		return []string{model.ScopeAdmin}
+	case RoleViewer:
+		return []string{model.ScopeTasksRead}
+	default:
+		return []string{model.ScopeTasksRead, model.ScopeTasksWrite}
+	}
+}
+
+// RoleForScopes returns the least privileged role covering scopes; API keys
+// have no role of their own
+func RoleForScopes(scopes []string) string {
+	role := RoleViewer
+	for _, s := range scopes {
+		switch s {
+		case model.ScopeAdmin:
+			return RoleAdmin
+		case model.ScopeTasksWrite:
+			role = RoleMember
+		}
+	}
+	return role
+}
+
+// Can reports whether the principal's role grants perm and its scopes allow it
+func (p Principal) Can(perm Permission) bool {
+	return p.roleGrants(perm) && p.HasScope(permissionScopes[perm])
+}
+
+// Forbidden describes why p may not perform perm
+func (p Principal) Forbidden(perm Permission) string {
+	if !p.roleGrants(perm) {
+		return fmt.Sprintf("Role %s does not grant %s", p.Role, perm)
+	}
+	return fmt.Sprintf("Permission %s requires scope %s", perm, permissionScopes[perm])
+}
+
+func (p Principal) roleGrants(perm Permission) bool {
+	for _, granted := range rolePermissions[p.Role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
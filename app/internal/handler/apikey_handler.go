@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// APIKeyHandler handles HTTP requests for API key management
+type APIKeyHandler struct {
+	service *service.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(svc *service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: svc}
+}
+
+// RegisterRoutes registers the API key routes
+func (h *APIKeyHandler) RegisterRoutes(r *gin.RouterGroup) {
+	registerRoutes(r, []route{
+		{http.MethodPost, "/api-keys", auth.PermManageAPIKeys, h.CreateAPIKey},
+		{http.MethodGet, "/api-keys", auth.PermManageAPIKeys, h.ListAPIKeys},
+		{http.MethodDelete, "/api-keys/:id", auth.PermManageAPIKeys, h.RevokeAPIKey},
+	})
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description The full key is only returned in this response
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param key body model.APIKeyCreateRequest true "Key to create"
+// @Success 201 {object} model.APIKeyCreatedResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /api/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req model.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	key, raw, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrValidation) {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{
+				Error:   "validation_error",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to create API key",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, model.APIKeyCreatedResponse{Data: *key, Key: raw})
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} model.APIKeyListResponse
+// @Router /api/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list API keys",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIKeyListResponse{Data: keys})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} model.APIKeyResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	key, err := h.service.Revoke(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{
+				Error:   "not_found",
+				Message: "API key not found",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to revoke API key",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.APIKeyResponse{Data: *key})
+}
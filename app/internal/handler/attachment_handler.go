@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// multipartOverhead is what an upload request may carry beyond the file:
+// part headers, boundaries and the sha256 field
+const multipartOverhead = 64 << 10
+
+// AttachmentHandler handles HTTP requests for task attachments
+type AttachmentHandler struct {
+	service         *service.AttachmentService
+	transferTimeout time.Duration
+}
+
+// NewAttachmentHandler creates a new attachment handler; uploads and
+// downloads may take up to transferTimeout
+func NewAttachmentHandler(svc *service.AttachmentService, transferTimeout time.Duration) *AttachmentHandler {
+	return &AttachmentHandler{service: svc, transferTimeout: transferTimeout}
+}
+
+// RegisterRoutes registers all attachment routes
+func (h *AttachmentHandler) RegisterRoutes(r *gin.RouterGroup) {
+	registerRoutes(r, []route{
+		{http.MethodPost, "/tasks/:id/attachments", auth.PermUpdateTasks, h.UploadAttachment},
+		{http.MethodGet, "/tasks/:id/attachments", auth.PermReadTasks, h.ListAttachments},
+		{http.MethodGet, "/tasks/:id/attachments/:attachment_id", auth.PermReadTasks, h.GetAttachment},
+		{http.MethodGet, "/tasks/:id/attachments/:attachment_id/content", auth.PermReadTasks, h.DownloadAttachment},
+		{http.MethodDelete, "/tasks/:id/attachments/:attachment_id", auth.PermUpdateTasks, h.DeleteAttachment},
+	})
+}
+
+// UploadAttachment godoc
+// @Summary Attach a file to a task
+// @Description Streams the "file" part of a multipart form into the blob store. An optional "sha256" field sent before the file is checked against the content. The content type is detected from the content.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param file formData file true "File to attach"
+// @Param sha256 formData string false "Expected hex SHA-256 of the file"
+// @Success 201 {object} model.AttachmentResponse
+// @Failure 400,404,413 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	h.extendDeadlines(c)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.service.MaxSize()+multipartOverhead)
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Expected a multipart/form-data body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var checksum string
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{
+				Error:   "validation_error",
+				Message: "file: a file part is required",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if err != nil {
+			respondAttachmentError(c, err, "Failed to read upload")
+			return
+		}
+
+		switch part.FormName() {
+		case "sha256":
+			value, err := io.ReadAll(io.LimitReader(part, 128))
+			if err != nil {
+				respondAttachmentError(c, err, "Failed to read upload")
+				return
+			}
+			checksum = string(value)
+		case "file":
+			attachment, err := h.service.Upload(c.Request.Context(), c.Param("id"), service.Upload{
+				Filename: part.FileName(),
+				Content:  part,
+				Checksum: checksum,
+			})
+			if err != nil {
+				respondAttachmentError(c, err, "Failed to upload attachment")
+				return
+			}
+			c.JSON(http.StatusCreated, model.AttachmentResponse{Data: *attachment})
+			return
+		}
+	}
+}
+
+// ListAttachments godoc
+// @Summary List the attachments of a task
+// @Tags attachments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} model.AttachmentListResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/attachments [get]
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	attachments, err := h.service.List(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to list attachments")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.AttachmentListResponse{Data: attachments})
+}
+
+// GetAttachment godoc
+// @Summary Get the metadata of an attachment
+// @Tags attachments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} model.AttachmentResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	attachment, err := h.service.GetByID(c.Request.Context(), c.Param("id"), c.Param("attachment_id"))
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to get attachment")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.AttachmentResponse{Data: *attachment})
+}
+
+// DownloadAttachment godoc
+// @Summary Download the content of an attachment
+// @Description Always served as a download. The ETag is the SHA-256 of the content; a stored blob that no longer matches it aborts the transfer.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/attachments/{attachment_id}/content [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	attachment, err := h.service.GetByID(ctx, c.Param("id"), c.Param("attachment_id"))
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to get attachment")
+		return
+	}
+
+	etag := `"` + attachment.Checksum + `"`
+	if etagMatchesNoneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	content, err := h.service.Open(ctx, attachment)
+	if err != nil {
+		respondAttachmentError(c, err, "Failed to download attachment")
+		return
+	}
+	defer content.Close()
+
+	h.extendDeadlines(c)
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   etag,
+	})
+}
+
+// DeleteAttachment godoc
+// @Summary Delete an attachment and its content
+// @Tags attachments
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), c.Param("attachment_id")); err != nil {
+		respondAttachmentError(c, err, "Failed to delete attachment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// extendDeadlines replaces the server's read and write timeouts for the
+// request with the transfer timeout, so large files are not cut off
+func (h *AttachmentHandler) extendDeadlines(c *gin.Context) {
+	deadline := time.Now().Add(h.transferTimeout)
+	rc := http.NewResponseController(c.Writer)
+	// Unsupported by some writers (e.g. in tests); the server timeouts then apply
+	_ = rc.SetReadDeadline(deadline)
+	_ = rc.SetWriteDeadline(deadline)
+}
+
+// respondAttachmentError maps an attachment service error to an ErrorResponse
+func respondAttachmentError(c *gin.Context, err error, message string) {
+	var checksumErr *service.ChecksumError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrAttachmentNotFound), errors.Is(err, service.ErrBlobNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Attachment not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, model.ErrorResponse{
+			Error:   "attachment_too_large",
+			Message: err.Error(),
+			Code:    http.StatusRequestEntityTooLarge,
+		})
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, model.ErrorResponse{
+			Error:   "attachment_too_large",
+			Message: "Request body exceeds the attachment size limit",
+			Code:    http.StatusRequestEntityTooLarge,
+		})
+	case errors.As(err, &checksumErr):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "checksum_mismatch",
+			Message: "Uploaded content does not match the sha256 field",
+			Code:    http.StatusBadRequest,
+			Details: gin.H{"expected": checksumErr.Expected, "actual": checksumErr.Actual},
+		})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
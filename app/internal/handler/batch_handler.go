@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/middleware"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// collectionAction is a custom method on the task collection. Its permission
+// is checked before the handler runs; an empty permission leaves the check to
+// the handler.
+type collectionAction struct {
+	permission auth.Permission
+	handler    func(h *TaskHandler, c *gin.Context)
+}
+
+// collectionActions maps action names, including their leading colon, to
+// their handlers
+var collectionActions = map[string]collectionAction{
+	// Batch operations are checked one by one, see batchOpPermissions
+	":batch":      {"", (*TaskHandler).BatchTasks},
+	":flushCache": {auth.PermFlushCache, (*TaskHandler).FlushCache},
+	// Sorting reads tasks only
+	":executionOrder": {auth.PermReadTasks, (*TaskHandler).ExecutionOrder},
+}
+
+// batchOpPermissions is the permission each batch operation requires
+var batchOpPermissions = map[string]auth.Permission{
+	model.BatchOpCreate: auth.PermCreateTasks,
+	model.BatchOpUpdate: auth.PermUpdateTasks,
+	model.BatchOpDelete: auth.PermDeleteTasks,
+}
+
+// TaskCollectionAction dispatches custom methods on the task collection.
+// Gin treats ":batch" in "/tasks:batch" as a parameter, so the action name
+// arrives with its leading colon.
+func (h *TaskHandler) TaskCollectionAction(c *gin.Context) {
+	action, ok := collectionActions[c.Param("action")]
+	if !ok {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Unknown task collection action",
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+	if action.permission != "" && !middleware.Authorize(c, action.permission) {
+		return
+	}
+	action.handler(h, c)
+}
+
+// FlushCache godoc
+// @Summary Drop every cached task
+// @Tags tasks
+// @Success 204
+// @Failure 403 {object} model.ErrorResponse
+// @Router /api/tasks:flushCache [post]
+func (h *TaskHandler) FlushCache(c *gin.Context) {
+	if err := h.service.FlushCache(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to flush cache",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// BatchTasks godoc
+// @Summary Create, update and delete tasks in bulk
+// @Description Each operation gets its own result. With "atomic": true the
+// @Description operations share one transaction and any failure rolls back all.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param batch body model.BatchRequest true "Operations to apply"
+// @Success 200 {object} model.BatchResponse
+// @Failure 400,404,412 {object} model.BatchResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /api/tasks:batch [post]
+func (h *TaskHandler) BatchTasks(c *gin.Context) {
+	var req model.BatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// A batch is rejected as a whole when any operation is not permitted
+	for _, op := range req.Operations {
+		if perm, ok := batchOpPermissions[op.Op]; ok && !middleware.Authorize(c, perm) {
+			return
+		}
+	}
+
+	outcomes, err := h.service.Batch(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to apply batch",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	resp := model.BatchResponse{Atomic: req.Atomic, Results: make([]model.BatchResult, len(outcomes))}
+	status := http.StatusOK
+
+	for i, o := range outcomes {
+		result := model.BatchResult{Index: i, Op: o.Op, ID: o.ID, Data: o.Task}
+
+		if o.Err != nil {
+			errResp := taskErrorResponse(o.Err, "Failed to apply operation")
+			result.Status, result.Error, result.Data = errResp.Code, &errResp, nil
+			resp.Failed++
+
+			// An atomic batch reports the status of the operation that failed it
+			if req.Atomic && !errors.Is(o.Err, service.ErrBatchAborted) {
+				status = errResp.Code
+			}
+		} else {
+			result.Status = batchSuccessStatus(o.Op)
+			resp.Succeeded++
+		}
+
+		resp.Results[i] = result
+	}
+
+	c.JSON(status, resp)
+}
+
+// batchSuccessStatus mirrors the status the single-task endpoint would return
+func batchSuccessStatus(op string) int {
+	switch op {
+	case model.BatchOpCreate:
+		return http.StatusCreated
+	case model.BatchOpDelete:
+		return http.StatusNoContent
+	default:
+		return http.StatusOK
+	}
+}
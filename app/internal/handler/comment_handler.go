@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// CommentHandler handles HTTP requests for task comments
+type CommentHandler struct {
+	service *service.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(svc *service.CommentService) *CommentHandler {
+	return &CommentHandler{service: svc}
+}
+
+// RegisterRoutes registers all comment routes. Editing or deleting someone
+// else's comment additionally requires auth.PermModerateComments.
+func (h *CommentHandler) RegisterRoutes(r *gin.RouterGroup) {
+	registerRoutes(r, []route{
+		{http.MethodPost, "/tasks/:id/comments", auth.PermWriteComments, h.CreateComment},
+		{http.MethodGet, "/tasks/:id/comments", auth.PermReadComments, h.ListComments},
+		{http.MethodGet, "/tasks/:id/comments/:comment_id", auth.PermReadComments, h.GetComment},
+		{http.MethodPut, "/tasks/:id/comments/:comment_id", auth.PermWriteComments, h.UpdateComment},
+		{http.MethodDelete, "/tasks/:id/comments/:comment_id", auth.PermWriteComments, h.DeleteComment},
+	})
+}
+
+// CreateComment godoc
+// @Summary Comment on a task
+// @Description Set parent_id to reply; a reply to a reply joins the thread of its top-level comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param comment body model.CommentCreateRequest true "Comment to create"
+// @Success 201 {object} model.CommentResponse
+// @Failure 400,404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var req model.CommentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comment, err := h.service.Create(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondCommentError(c, err, "Failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.CommentResponse{Data: *comment})
+}
+
+// ListComments godoc
+// @Summary List the comments of a task, oldest first
+// @Description Lists top-level comments, or the replies to parent_id. Pass next_cursor as cursor for the next page.
+// @Tags comments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param parent_id query string false "List the replies to this comment"
+// @Param cursor query string false "Opaque cursor from next_cursor"
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} model.CommentListResponse
+// @Failure 400,404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	result, err := h.service.List(c.Request.Context(), c.Param("id"), c.Query("parent_id"), c.Query("cursor"), perPage)
+	if err != nil {
+		respondCommentError(c, err, "Failed to list comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetComment godoc
+// @Summary Get a comment with its edit history
+// @Tags comments
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} model.CommentResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/comments/{comment_id} [get]
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	comment, err := h.service.GetByID(c.Request.Context(), c.Param("id"), c.Param("comment_id"))
+	if err != nil {
+		respondCommentError(c, err, "Failed to get comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommentResponse{Data: *comment})
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description The previous body is appended to the comment's history. Only the author or an admin may edit a comment.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Param comment body model.CommentUpdateRequest true "New body"
+// @Success 200 {object} model.CommentResponse
+// @Failure 400,403,404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	var req model.CommentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	comment, err := h.service.Update(c.Request.Context(), c.Param("id"), c.Param("comment_id"), req)
+	if err != nil {
+		respondCommentError(c, err, "Failed to update comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.CommentResponse{Data: *comment})
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description A comment with replies is kept, without its body, as deleted until its last reply is gone. Only the author or an admin may delete a comment.
+// @Tags comments
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 204
+// @Failure 403,404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id"), c.Param("comment_id")); err != nil {
+		respondCommentError(c, err, "Failed to delete comment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondCommentError maps a comment service error to an ErrorResponse
+func respondCommentError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Comment not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrInvalidCommentParent):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_parent",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, service.ErrCommentForbidden):
+		c.JSON(http.StatusForbidden, model.ErrorResponse{
+			Error:   "forbidden",
+			Message: "Only the author or an admin may change this comment",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
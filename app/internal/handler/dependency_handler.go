@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// ListBlockers godoc
+// @Summary List the tasks blocking a task
+// @Description Returns open and closed blockers; only open ones keep the task from being started or completed
+// @Tags dependencies
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} model.BlockerListResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/blockers [get]
+func (h *TaskHandler) ListBlockers(c *gin.Context) {
+	blockers, err := h.service.Blockers(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to list blockers")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.BlockerListResponse{Data: blockers})
+}
+
+// AddBlocker godoc
+// @Summary Mark a task as blocked by another task
+// @Description Fails with 409 when the blocker already waits on the task, directly or indirectly
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param blocker body model.BlockerRequest true "Blocking task"
+// @Success 201 {object} model.DependencyResponse
+// @Failure 400,404,409 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/blockers [post]
+func (h *TaskHandler) AddBlocker(c *gin.Context) {
+	var req model.BlockerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	dep, err := h.service.AddBlocker(c.Request.Context(), c.Param("id"), req.BlockerID)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to add blocker")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.DependencyResponse{Data: *dep})
+}
+
+// RemoveBlocker godoc
+// @Summary Remove a blocker from a task
+// @Tags dependencies
+// @Param id path string true "Task ID"
+// @Param blocker_id path string true "Blocking task ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/blockers/{blocker_id} [delete]
+func (h *TaskHandler) RemoveBlocker(c *gin.Context) {
+	if err := h.service.RemoveBlocker(c.Request.Context(), c.Param("id"), c.Param("blocker_id")); err != nil {
+		respondTaskWriteError(c, err, "Failed to remove blocker")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExecutionOrder godoc
+// @Summary Sort tasks so that every task follows its blockers
+// @Description Only dependencies among the given tasks count. stages groups the IDs into steps whose tasks can run in parallel.
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param request body model.ExecutionOrderRequest true "Tasks to sort"
+// @Success 200 {object} model.ExecutionOrderResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /api/tasks:executionOrder [post]
+func (h *TaskHandler) ExecutionOrder(c *gin.Context) {
+	var req model.ExecutionOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	order, err := h.service.ExecutionOrder(c.Request.Context(), req.IDs)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to compute execution order")
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
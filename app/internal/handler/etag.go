@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// errMultipleETags is returned for an If-Match listing more than one tag
+var errMultipleETags = errors.New("If-Match must contain a single entity tag or *")
+
+// taskETag returns the strong entity tag for a task's current version
+func taskETag(task *model.Task) string {
+	return `"` + strconv.Itoa(task.Version) + `"`
+}
+
+// parseIfMatch returns the version required by an If-Match header.
+// An absent header or "*" yields 0 (no version check). A tag that cannot
+// match any version, such as a weak tag, yields -1 so the write fails with 412.
+func parseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+	if strings.Contains(header, ",") {
+		return 0, errMultipleETags
+	}
+
+	// If-Match uses strong comparison, so weak tags never match
+	if !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) || len(header) < 2 {
+		return -1, nil
+	}
+	version, err := strconv.Atoi(header[1 : len(header)-1])
+	if err != nil || version < 1 {
+		return -1, nil
+	}
+	return version, nil
+}
+
+// etagMatchesNoneMatch reports whether an If-None-Match header matches etag
+// using weak comparison, as RFC 9110 requires for GET
+func etagMatchesNoneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// ProjectHandler handles HTTP requests for projects
+type ProjectHandler struct {
+	service *service.ProjectService
+}
+
+// NewProjectHandler creates a new project handler
+func NewProjectHandler(svc *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{service: svc}
+}
+
+// RegisterRoutes registers all project routes
+func (h *ProjectHandler) RegisterRoutes(r *gin.RouterGroup) {
+	registerRoutes(r, []route{
+		{http.MethodPost, "/projects", auth.PermManageProjects, h.CreateProject},
+		{http.MethodGet, "/projects", auth.PermReadProjects, h.ListProjects},
+		{http.MethodGet, "/projects/:id", auth.PermReadProjects, h.GetProject},
+		{http.MethodPut, "/projects/:id", auth.PermManageProjects, h.UpdateProject},
+		{http.MethodDelete, "/projects/:id", auth.PermDeleteProjects, h.DeleteProject},
+		{http.MethodGet, "/projects/:id/summary", auth.PermReadProjects, h.GetProjectSummary},
+	})
+}
+
+// CreateProject godoc
+// @Summary Create a new project
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project body model.ProjectCreateRequest true "Project to create"
+// @Success 201 {object} model.ProjectResponse
+// @Failure 400,409 {object} model.ErrorResponse
+// @Router /api/projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req model.ProjectCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	project, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		respondProjectError(c, err, "Failed to create project")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.ProjectResponse{Data: *project})
+}
+
+// GetProject godoc
+// @Summary Get a project by ID
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} model.ProjectResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	project, err := h.service.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondProjectError(c, err, "Failed to get project")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.ProjectResponse{Data: *project})
+}
+
+// ListProjects godoc
+// @Summary List projects
+// @Tags projects
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param archived query bool false "Include archived projects" default(false)
+// @Success 200 {object} model.ProjectListResponse
+// @Router /api/projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+	includeArchived, _ := strconv.ParseBool(c.DefaultQuery("archived", "false"))
+
+	result, err := h.service.List(c.Request.Context(), page, perPage, includeArchived)
+	if err != nil {
+		respondProjectError(c, err, "Failed to list projects")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateProject godoc
+// @Summary Update a project
+// @Description Archived projects accept no new tasks; an empty owner_id clears the owner
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param project body model.ProjectUpdateRequest true "Fields to update"
+// @Success 200 {object} model.ProjectResponse
+// @Failure 400,404,409 {object} model.ErrorResponse
+// @Router /api/projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	var req model.ProjectUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	project, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondProjectError(c, err, "Failed to update project")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.ProjectResponse{Data: *project})
+}
+
+// DeleteProject godoc
+// @Summary Delete a project
+// @Description Only projects without tasks can be deleted
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 204
+// @Failure 404,409 {object} model.ErrorResponse
+// @Router /api/projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondProjectError(c, err, "Failed to delete project")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetProjectSummary godoc
+// @Summary Get task counts and progress of a project
+// @Description Counts tasks per status and priority; percent_complete is the share of completed tasks among those not cancelled
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} model.ProjectSummaryResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/projects/{id}/summary [get]
+func (h *ProjectHandler) GetProjectSummary(c *gin.Context) {
+	summary, err := h.service.Summary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondProjectError(c, err, "Failed to summarize project")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.ProjectSummaryResponse{Data: *summary})
+}
+
+// respondProjectError maps a project service error to an ErrorResponse
+func respondProjectError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Project not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrProjectExists):
+		c.JSON(http.StatusConflict, model.ErrorResponse{
+			Error:   "project_exists",
+			Message: "A project with this name already exists",
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, service.ErrProjectNotEmpty):
+		c.JSON(http.StatusConflict, model.ErrorResponse{
+			Error:   "project_not_empty",
+			Message: "Project still has tasks; move or delete them first",
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, service.ErrValidation):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/middleware"
+)
+
+// route declares an endpoint together with the permission it requires
+type route struct {
+	method     string
+	path       string
+	permission auth.Permission
+	handler    gin.HandlerFunc
+}
+
+// registerRoutes adds routes to r, each guarded by its permission
+func registerRoutes(r gin.IRoutes, routes []route) {
+	for _, rt := range routes {
+		r.Handle(rt.method, rt.path, middleware.RequirePermission(rt.permission), rt.handler)
+	}
+}
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/hamfa/task-manager/internal/auth"
 	"github.com/hamfa/task-manager/internal/model"
 	"github.com/hamfa/task-manager/internal/service"
 )
@@ -22,15 +24,30 @@ func NewTaskHandler(svc *service.TaskService) *TaskHandler {
 
 // RegisterRoutes registers all task routes
 func (h *TaskHandler) RegisterRoutes(r *gin.RouterGroup) {
-	tasks := r.Group("/tasks")
-	{
-		tasks.POST("", h.CreateTask)
-		tasks.GET("", h.ListTasks)
-		tasks.GET("/:id", h.GetTask)
-		tasks.PUT("/:id", h.UpdateTask)
-		tasks.DELETE("/:id", h.DeleteTask)
-		tasks.GET("/:id/activities", h.GetTaskActivities)
-	}
+	registerRoutes(r, []route{
+		{http.MethodPost, "/tasks", auth.PermCreateTasks, h.CreateTask},
+		{http.MethodGet, "/tasks", auth.PermReadTasks, h.ListTasks},
+		{http.MethodGet, "/tasks/:id", auth.PermReadTasks, h.GetTask},
+		{http.MethodPut, "/tasks/:id", auth.PermUpdateTasks, h.UpdateTask},
+		{http.MethodPatch, "/tasks/:id", auth.PermUpdateTasks, h.PatchTask},
+		{http.MethodDelete, "/tasks/:id", auth.PermDeleteTasks, h.DeleteTask},
+		{http.MethodGet, "/tasks/trash", auth.PermReadTasks, h.ListTrash},
+		{http.MethodPost, "/tasks/:id/restore", auth.PermDeleteTasks, h.RestoreTask},
+		{http.MethodDelete, "/tasks/trash/:id", auth.PermPurgeTasks, h.PurgeTask},
+		{http.MethodGet, "/tasks/:id/activities", auth.PermReadActivities, h.GetTaskActivities},
+		{http.MethodGet, "/tasks/:id/history", auth.PermReadActivities, h.GetTaskHistory},
+		{http.MethodPost, "/tasks/:id/revert", auth.PermUpdateTasks, h.RevertTask},
+		{http.MethodGet, "/tasks/:id/children", auth.PermReadTasks, h.ListSubtasks},
+		{http.MethodGet, "/tasks/:id/tree", auth.PermReadTasks, h.GetTaskTree},
+		{http.MethodGet, "/tasks/:id/blockers", auth.PermReadTasks, h.ListBlockers},
+		{http.MethodPost, "/tasks/:id/blockers", auth.PermUpdateTasks, h.AddBlocker},
+		{http.MethodDelete, "/tasks/:id/blockers/:blocker_id", auth.PermUpdateTasks, h.RemoveBlocker},
+		{http.MethodGet, "/tags", auth.PermReadTasks, h.ListTags},
+	})
+
+	// Custom methods on the collection, e.g. POST /tasks:batch; each action
+	// checks its own permissions (see collectionActions)
+	r.POST("/tasks:action", h.TaskCollectionAction)
 }
 
 // CreateTask godoc
@@ -55,14 +72,11 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 	task, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to create task",
-			Code:    http.StatusInternalServerError,
-		})
+		respondTaskWriteError(c, err, "Failed to create task")
 		return
 	}
 
+	c.Header("ETag", taskETag(task))
 	c.JSON(http.StatusCreated, model.TaskResponse{Data: *task})
 }
 
@@ -71,7 +85,9 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 // @Tags tasks
 // @Produce json
 // @Param id path string true "Task ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} model.TaskResponse
+// @Success 304
 // @Failure 404 {object} model.ErrorResponse
 // @Router /api/tasks/{id} [get]
 func (h *TaskHandler) GetTask(c *gin.Context) {
@@ -87,6 +103,13 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 		return
 	}
 
+	etag := taskETag(task)
+	c.Header("ETag", etag)
+	if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatchesNoneMatch(inm, etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, model.TaskResponse{Data: *task})
 }
 
@@ -94,17 +117,36 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 // @Summary List all tasks
 // @Tags tasks
 // @Produce json
-// @Param page query int false "Page number" default(1)
+// @Param page query int false "Page number (ignored when cursor is set)" default(1)
 // @Param per_page query int false "Items per page" default(20)
-// @Param status query string false "Filter by status"
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param include_total query bool false "Include the total count" default(true)
+// @Param q query string false "Full-text search over title and description"
+// @Param status query string false "Comma-separated statuses"
+// @Param priority query string false "Comma-separated priorities"
+// @Param tags_any query string false "Comma-separated tags; matches tasks with any of them"
+// @Param tags_all query string false "Comma-separated tags; matches tasks with all of them"
+// @Param created_after query string false "RFC 3339 lower bound (inclusive) on created_at"
+// @Param created_before query string false "RFC 3339 upper bound (exclusive) on created_at"
+// @Param updated_after query string false "RFC 3339 lower bound (inclusive) on updated_at"
+// @Param updated_before query string false "RFC 3339 upper bound (exclusive) on updated_at"
+// @Param sort query string false "created_at, updated_at, priority, title or relevance (default when q is set)" default(created_at)
+// @Param order query string false "asc or desc" default(desc)
 // @Success 200 {object} model.TaskListResponse
+// @Failure 400 {object} model.ErrorResponse
 // @Router /api/tasks [get]
 func (h *TaskHandler) ListTasks(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
-	status := c.Query("status")
+	query, err := bindTaskListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
 
-	result, err := h.service.List(c.Request.Context(), page, perPage, status)
+	result, err := h.service.List(c.Request.Context(), query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
 			Error:   "internal_error",
@@ -123,13 +165,24 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Param task body model.TaskUpdateRequest true "Task updates"
 // @Success 200 {object} model.TaskResponse
-// @Failure 400,404 {object} model.ErrorResponse
+// @Failure 400,404,412 {object} model.ErrorResponse
 // @Router /api/tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id := c.Param("id")
 
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
 	var req model.TaskUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{
@@ -140,41 +193,220 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.service.Update(c.Request.Context(), id, req)
+	task, err := h.service.Update(c.Request.Context(), id, req, expectedVersion)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.ErrorResponse{
-			Error:   "not_found",
-			Message: "Task not found",
-			Code:    http.StatusNotFound,
+		respondTaskWriteError(c, err, "Failed to update task")
+		return
+	}
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, model.TaskResponse{Data: *task})
+}
+
+// PatchTask godoc
+// @Summary Partially update a task
+// @Description Accepts application/merge-patch+json (RFC 7396) or
+// @Description application/json-patch+json (RFC 6902, including "test").
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param If-Match header string false "ETag the patch is conditional on"
+// @Success 200 {object} model.TaskResponse
+// @Failure 400,404,409,412,415 {object} model.ErrorResponse
+// @Router /api/tasks/{id} [patch]
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	id := c.Param("id")
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "invalid_patch",
+			Message: "Failed to read request body",
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
+	task, err := h.service.Patch(c.Request.Context(), id, c.ContentType(), patch, expectedVersion)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to patch task")
+		return
+	}
+
+	c.Header("ETag", taskETag(task))
 	c.JSON(http.StatusOK, model.TaskResponse{Data: *task})
 }
 
 // DeleteTask godoc
-// @Summary Delete a task
+// @Summary Move a task to the trash
+// @Description Subtasks are rejected, trashed along with the task or detached according to SUBTASK_ON_DELETE.
 // @Tags tasks
 // @Param id path string true "Task ID"
+// @Param If-Match header string false "ETag the delete is conditional on"
 // @Success 204
-// @Failure 404 {object} model.ErrorResponse
+// @Failure 400,404,409,412 {object} model.ErrorResponse
 // @Router /api/tasks/{id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusNotFound, model.ErrorResponse{
-			Error:   "not_found",
-			Message: "Task not found",
-			Code:    http.StatusNotFound,
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
+	if err := h.service.Delete(c.Request.Context(), id, expectedVersion); err != nil {
+		respondTaskWriteError(c, err, "Failed to delete task")
+		return
+	}
+
 	c.Status(http.StatusNoContent)
 }
 
+// ListTrash godoc
+// @Summary List the tasks in the trash
+// @Description Accepts the filters, sorting and pagination of GET /api/tasks.
+// @Tags tasks
+// @Produce json
+// @Param page query int false "Page number (ignored when cursor is set)" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param status query string false "Comma-separated statuses"
+// @Param sort query string false "created_at, updated_at, priority or title" default(created_at)
+// @Param order query string false "asc or desc" default(desc)
+// @Success 200 {object} model.TaskListResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /api/tasks/trash [get]
+func (h *TaskHandler) ListTrash(c *gin.Context) {
+	query, err := bindTaskListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.service.ListTrash(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to list trash",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RestoreTask godoc
+// @Summary Restore a task from the trash
+// @Description Subtasks trashed along with the task are restored with it. A task whose parent is still in the trash cannot be restored.
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} model.TaskResponse
+// @Failure 404,409 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	task, err := h.service.Restore(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to restore task")
+		return
+	}
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, model.TaskResponse{Data: *task})
+}
+
+// PurgeTask godoc
+// @Summary Permanently delete a task from the trash
+// @Description Also purges its trashed subtasks and the attachments of both.
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/trash/{id} [delete]
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+	if err := h.service.Purge(c.Request.Context(), c.Param("id")); err != nil {
+		respondTaskWriteError(c, err, "Failed to purge task")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListSubtasks godoc
+// @Summary List the direct subtasks of a task
+// @Description Accepts the filters, sorting and pagination of GET /api/tasks. Subtasks that have subtasks of their own carry their rollup progress.
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param page query int false "Page number (ignored when cursor is set)" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Param cursor query string false "Opaque cursor from next_cursor or prev_cursor"
+// @Param status query string false "Comma-separated statuses"
+// @Param sort query string false "created_at, updated_at, priority or title" default(created_at)
+// @Param order query string false "asc or desc" default(desc)
+// @Success 200 {object} model.TaskListResponse
+// @Failure 400,404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/children [get]
+func (h *TaskHandler) ListSubtasks(c *gin.Context) {
+	query, err := bindTaskListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.service.ListChildren(c.Request.Context(), c.Param("id"), query)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to list subtasks")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetTaskTree godoc
+// @Summary Get a task with all of its subtasks nested below it
+// @Description Tasks with subtasks carry the rollup progress of their subtasks at every depth
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} model.TaskTreeResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/tree [get]
+func (h *TaskHandler) GetTaskTree(c *gin.Context) {
+	tree, err := h.service.Tree(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to get task tree")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TaskTreeResponse{Data: *tree})
+}
+
 // GetTaskActivities returns activity logs for a task
 func (h *TaskHandler) GetTaskActivities(c *gin.Context) {
 	id := c.Param("id")
@@ -192,3 +424,231 @@ func (h *TaskHandler) GetTaskActivities(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"data": activities})
 }
+
+// GetTaskHistory godoc
+// @Summary Get the change history of a task
+// @Description Every update lists the fields it changed with their values before and after; description changes also carry a unified diff. Entries recorded before field changes were tracked only have their details.
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param limit query int false "Maximum number of entries" default(50)
+// @Success 200 {object} model.TaskHistoryResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/history [get]
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "50"), 10, 64)
+
+	history, err := h.service.History(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to get task history")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TaskHistoryResponse{Data: history})
+}
+
+// RevertTask godoc
+// @Summary Revert a task to an earlier version
+// @Description Restores the title, description, status and priority the task had right after the given "created" or "updated" activity. The change is applied as a regular update, so the workflow and other update rules apply, and is logged as a "reverted" activity.
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param to query string true "ID of the activity to revert to, from GET /api/tasks/{id}/history"
+// @Param If-Match header string false "ETag the revert is conditional on"
+// @Success 200 {object} model.TaskResponse
+// @Failure 400,404,409,412 {object} model.ErrorResponse
+// @Router /api/tasks/{id}/revert [post]
+func (h *TaskHandler) RevertTask(c *gin.Context) {
+	to := c.Query("to")
+	if to == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: "to: the activity ID to revert to is required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	task, err := h.service.Revert(c.Request.Context(), c.Param("id"), to, expectedVersion)
+	if err != nil {
+		respondTaskWriteError(c, err, "Failed to revert task")
+		return
+	}
+
+	c.Header("ETag", taskETag(task))
+	c.JSON(http.StatusOK, model.TaskResponse{Data: *task})
+}
+
+// ListTags godoc
+// @Summary List tags with the number of tasks carrying each
+// @Tags tasks
+// @Produce json
+// @Success 200 {object} model.TagListResponse
+// @Router /api/tags [get]
+func (h *TaskHandler) ListTags(c *gin.Context) {
+	counts, err := h.service.TagCounts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: "Failed to count tags",
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.TagListResponse{Data: counts})
+}
+
+// respondTaskWriteError maps a task write failure to an ErrorResponse
+func respondTaskWriteError(c *gin.Context, err error, message string) {
+	resp := taskErrorResponse(err, message)
+	c.JSON(resp.Code, resp)
+}
+
+// taskErrorResponse maps a task write failure to an ErrorResponse;
+// message is used for unexpected errors
+func taskErrorResponse(err error, message string) model.ErrorResponse {
+	var transitionErr *service.TransitionError
+	var openErr *service.OpenSubtasksError
+	var blockedErr *service.BlockedError
+
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		return model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Task not found",
+			Code:    http.StatusNotFound,
+		}
+	case errors.Is(err, service.ErrVersionConflict):
+		return model.ErrorResponse{
+			Error:   "precondition_failed",
+			Message: "Task has been modified; fetch it again and retry",
+			Code:    http.StatusPreconditionFailed,
+		}
+	case errors.Is(err, service.ErrValidation):
+		return model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+	case errors.Is(err, service.ErrInvalidPatch):
+		return model.ErrorResponse{
+			Error:   "invalid_patch",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+	case errors.Is(err, service.ErrPatchTestFailed):
+		return model.ErrorResponse{
+			Error:   "patch_test_failed",
+			Message: err.Error(),
+			Code:    http.StatusConflict,
+		}
+	case errors.Is(err, service.ErrUnsupportedPatch):
+		return model.ErrorResponse{
+			Error:   "unsupported_media_type",
+			Message: "Use application/merge-patch+json or application/json-patch+json",
+			Code:    http.StatusUnsupportedMediaType,
+		}
+	case errors.As(err, &transitionErr):
+		return model.ErrorResponse{
+			Error:   "invalid_transition",
+			Message: transitionErr.Error(),
+			Code:    http.StatusConflict,
+			Details: gin.H{
+				"from":    transitionErr.From,
+				"to":      transitionErr.To,
+				"allowed": transitionErr.Allowed,
+			},
+		}
+	case errors.Is(err, service.ErrInvalidParent):
+		return model.ErrorResponse{
+			Error:   "invalid_parent",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+	case errors.Is(err, service.ErrHasSubtasks):
+		return model.ErrorResponse{
+			Error:   "has_subtasks",
+			Message: "Task has subtasks; delete or move them first",
+			Code:    http.StatusConflict,
+		}
+	case errors.Is(err, service.ErrRevertTargetNotFound):
+		return model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Activity to revert to not found in the task's history",
+			Code:    http.StatusNotFound,
+		}
+	case errors.Is(err, service.ErrHistoryIncomplete):
+		return model.ErrorResponse{
+			Error:   "history_incomplete",
+			Message: "Task history is missing field changes needed to revert that far",
+			Code:    http.StatusConflict,
+		}
+	case errors.Is(err, service.ErrParentInTrash):
+		return model.ErrorResponse{
+			Error:   "parent_in_trash",
+			Message: "Parent task is in the trash; restore it first",
+			Code:    http.StatusConflict,
+		}
+	case errors.As(err, &openErr):
+		return model.ErrorResponse{
+			Error:   "open_subtasks",
+			Message: openErr.Error(),
+			Code:    http.StatusConflict,
+			Details: gin.H{"open_subtasks": openErr.Open},
+		}
+	case errors.As(err, &blockedErr):
+		return model.ErrorResponse{
+			Error:   "blocked",
+			Message: blockedErr.Error(),
+			Code:    http.StatusConflict,
+			Details: gin.H{"blockers": blockedErr.Blockers},
+		}
+	case errors.Is(err, service.ErrInvalidDependency):
+		return model.ErrorResponse{
+			Error:   "invalid_dependency",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		}
+	case errors.Is(err, service.ErrDependencyExists):
+		return model.ErrorResponse{
+			Error:   "dependency_exists",
+			Message: "Task is already blocked by this task",
+			Code:    http.StatusConflict,
+		}
+	case errors.Is(err, service.ErrDependencyCycle):
+		return model.ErrorResponse{
+			Error:   "dependency_cycle",
+			Message: "The blocker already waits on this task; the dependency would create a cycle",
+			Code:    http.StatusConflict,
+		}
+	case errors.Is(err, service.ErrDependencyNotFound):
+		return model.ErrorResponse{
+			Error:   "not_found",
+			Message: "Task is not blocked by this task",
+			Code:    http.StatusNotFound,
+		}
+	case errors.Is(err, service.ErrBatchAborted):
+		return model.ErrorResponse{
+			Error:   "aborted",
+			Message: err.Error(),
+			Code:    http.StatusFailedDependency,
+		}
+	default:
+		return model.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		}
+	}
+}
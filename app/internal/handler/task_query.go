@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// bindTaskListQuery parses and validates the GET /api/tasks query string
+func bindTaskListQuery(c *gin.Context) (model.TaskListQuery, error) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	includeTotal, err := strconv.ParseBool(c.DefaultQuery("include_total", "true"))
+	if err != nil {
+		return model.TaskListQuery{}, fmt.Errorf("include_total must be a boolean")
+	}
+
+	q := model.TaskListQuery{
+		Page:         page,
+		PerPage:      perPage,
+		IncludeTotal: includeTotal,
+		Sort:         c.Query("sort"),
+		Order:        strings.ToLower(c.Query("order")),
+		Filter: model.TaskFilter{
+			Search:      strings.TrimSpace(c.Query("q")),
+			Statuses:    splitList(c.Query("status")),
+			Priorities:  splitList(c.Query("priority")),
+			AssigneeIDs: splitList(c.Query("assignee_id")),
+			ReporterIDs: splitList(c.Query("reporter_id")),
+			ProjectIDs:  splitList(c.Query("project_id")),
+			ParentIDs:   splitList(c.Query("parent_id")),
+		},
+	}
+
+	tags := []struct {
+		param string
+		dst   *[]string
+	}{
+		{"tags_any", &q.Filter.TagsAny},
+		{"tags_all", &q.Filter.TagsAll},
+	}
+	for _, t := range tags {
+		normalized, err := model.NormalizeTags(splitList(c.Query(t.param)))
+		if err != nil {
+			return model.TaskListQuery{}, fmt.Errorf("%s: %v", t.param, err)
+		}
+		*t.dst = normalized
+	}
+
+	times := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_after", &q.Filter.CreatedAfter},
+		{"created_before", &q.Filter.CreatedBefore},
+		{"updated_after", &q.Filter.UpdatedAfter},
+		{"updated_before", &q.Filter.UpdatedBefore},
+		{"due_after", &q.Filter.DueAfter},
+		{"due_before", &q.Filter.DueBefore},
+	}
+	for _, t := range times {
+		raw := c.Query(t.param)
+		if raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return model.TaskListQuery{}, fmt.Errorf("%s must be an RFC 3339 timestamp", t.param)
+		}
+		*t.dst = &parsed
+	}
+
+	if err := bindDueFilters(c, &q.Filter, time.Now()); err != nil {
+		return model.TaskListQuery{}, err
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := model.DecodeTaskCursor(raw)
+		if err != nil {
+			return model.TaskListQuery{}, err
+		}
+		q.Cursor = cursor
+	}
+
+	q.Normalize()
+	if err := q.Validate(); err != nil {
+		return model.TaskListQuery{}, err
+	}
+
+	return q, nil
+}
+
+// bindDueFilters turns overdue=true and due_within=<hours> into a due date
+// range over open tasks, relative to now
+func bindDueFilters(c *gin.Context, f *model.TaskFilter, now time.Time) error {
+	overdue, err := strconv.ParseBool(c.DefaultQuery("overdue", "false"))
+	if err != nil {
+		return fmt.Errorf("overdue must be a boolean")
+	}
+
+	var within int
+	if raw := c.Query("due_within"); raw != "" {
+		within, err = strconv.Atoi(raw)
+		if err != nil || within <= 0 {
+			return fmt.Errorf("due_within must be a positive number of hours")
+		}
+	}
+
+	if !overdue && within == 0 {
+		return nil
+	}
+	if overdue && within > 0 {
+		return fmt.Errorf("overdue and due_within cannot be combined")
+	}
+	if f.DueAfter != nil || f.DueBefore != nil {
+		return fmt.Errorf("overdue and due_within cannot be combined with due_after or due_before")
+	}
+
+	f.OpenOnly = true
+	if overdue {
+		f.DueBefore = &now
+		return nil
+	}
+	until := now.Add(time.Duration(within) * time.Hour)
+	f.DueAfter, f.DueBefore = &now, &until
+	return nil
+}
+
+// splitList splits a comma-separated query value, dropping empty items
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/service"
+)
+
+// UserHandler handles HTTP requests for users
+type UserHandler struct {
+	service *service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(svc *service.UserService) *UserHandler {
+	return &UserHandler{service: svc}
+}
+
+// RegisterRoutes registers all user routes
+func (h *UserHandler) RegisterRoutes(r *gin.RouterGroup) {
+	registerRoutes(r, []route{
+		{http.MethodPost, "/users", auth.PermManageUsers, h.CreateUser},
+		{http.MethodGet, "/users", auth.PermReadUsers, h.ListUsers},
+		{http.MethodGet, "/users/:id", auth.PermReadUsers, h.GetUser},
+		{http.MethodPut, "/users/:id", auth.PermManageUsers, h.UpdateUser},
+		{http.MethodDelete, "/users/:id", auth.PermManageUsers, h.DeleteUser},
+		{http.MethodGet, "/users/:id/tasks", auth.PermReadTasks, h.ListUserTasks},
+	})
+}
+
+// CreateUser godoc
+// @Summary Create a new user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body model.UserCreateRequest true "User to create"
+// @Success 201 {object} model.UserResponse
+// @Failure 400,409 {object} model.ErrorResponse
+// @Router /api/users [post]
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req model.UserCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		respondUserError(c, err, "Failed to create user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.UserResponse{Data: *user})
+}
+
+// GetUser godoc
+// @Summary Get a user by ID
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.UserResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/users/{id} [get]
+func (h *UserHandler) GetUser(c *gin.Context) {
+	user, err := h.service.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondUserError(c, err, "Failed to get user")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.UserResponse{Data: *user})
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Tags users
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param per_page query int false "Items per page" default(20)
+// @Success 200 {object} model.UserListResponse
+// @Router /api/users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+
+	result, err := h.service.List(c.Request.Context(), page, perPage)
+	if err != nil {
+		respondUserError(c, err, "Failed to list users")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateUser godoc
+// @Summary Update a user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body model.UserUpdateRequest true "Fields to update"
+// @Success 200 {object} model.UserResponse
+// @Failure 400,404,409 {object} model.ErrorResponse
+// @Router /api/users/{id} [put]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	var req model.UserUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := h.service.Update(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		respondUserError(c, err, "Failed to update user")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.UserResponse{Data: *user})
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description The user is unassigned from their tasks and cleared as reporter
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /api/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		respondUserError(c, err, "Failed to delete user")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListUserTasks godoc
+// @Summary List the tasks assigned to a user
+// @Description Accepts the same query parameters as GET /api/tasks
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.TaskListResponse
+// @Failure 400,404 {object} model.ErrorResponse
+// @Router /api/users/{id}/tasks [get]
+func (h *UserHandler) ListUserTasks(c *gin.Context) {
+	query, err := bindTaskListQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.service.ListTasks(c.Request.Context(), c.Param("id"), query)
+	if err != nil {
+		respondUserError(c, err, "Failed to list tasks")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// respondUserError maps a user service error to an ErrorResponse
+func respondUserError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{
+			Error:   "not_found",
+			Message: "User not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, service.ErrUserExists):
+		c.JSON(http.StatusConflict, model.ErrorResponse{
+			Error:   "user_exists",
+			Message: "Username or email is already in use",
+			Code:    http.StatusConflict,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
+			Error:   "internal_error",
+			Message: message,
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// SubjectKey is the gin context key holding the authenticated subject
+const SubjectKey = "auth.subject"
+
+// Authenticate returns a gin middleware that requires an Authorization header
+// with one of the given schemes, e.g. "Bearer" or "ApiKey" (matched
+// case-insensitively). The principal is stored on the request context (see
+// auth.FromContext) and its subject under SubjectKey.
+func Authenticate(schemes map[string]auth.Authenticator, logger *zap.Logger) gin.HandlerFunc {
+	byScheme := make(map[string]auth.Authenticator, len(schemes))
+	var challenges []string
+	for scheme, authenticator := range schemes {
+		byScheme[strings.ToLower(scheme)] = authenticator
+		challenges = append(challenges, scheme+` realm="task-manager"`)
+	}
+	sort.Strings(challenges)
+	challenge := strings.Join(challenges, ", ")
+
+	return func(c *gin.Context) {
+		scheme, credentials, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+		authenticator, ok := byScheme[strings.ToLower(scheme)]
+		credentials = strings.TrimSpace(credentials)
+		if !ok || credentials == "" {
+			abortUnauthorized(c, challenge, "Missing credentials")
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), credentials)
+		if err != nil {
+			logger.Debug("rejected credentials", zap.String("scheme", scheme), zap.Error(err))
+			abortUnauthorized(c, challenge, "Invalid, expired or revoked credentials")
+			return
+		}
+
+		setPrincipal(c, principal)
+		c.Next()
+	}
+}
+
+// Anonymous returns a gin middleware that gives every request the
+// auth.Anonymous principal; it is used when authentication is disabled
+func Anonymous() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		setPrincipal(c, auth.Anonymous)
+		c.Next()
+	}
+}
+
+// RequirePermission returns a gin middleware that rejects principals whose
+// role or scopes do not grant perm
+func RequirePermission(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !Authorize(c, perm) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// Authorize checks perm for the request's principal. When it is not granted
+// the request is aborted with 401 or 403 and Authorize returns false.
+func Authorize(c *gin.Context, perm auth.Permission) bool {
+	principal, ok := auth.FromContext(c.Request.Context())
+	if !ok {
+		abortUnauthorized(c, "", "Missing credentials")
+		return false
+	}
+	if !principal.Can(perm) {
+		c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
+			Error:   "forbidden",
+			Message: principal.Forbidden(perm),
+			Code:    http.StatusForbidden,
+			Details: gin.H{"permission": perm, "role": principal.Role},
+		})
+		return false
+	}
+	return true
+}
+
+func setPrincipal(c *gin.Context, p auth.Principal) {
+	c.Set(SubjectKey, p.Subject)
+	c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+}
+
+func abortUnauthorized(c *gin.Context, challenge, message string) {
+	if challenge != "" {
+		c.Header("WWW-Authenticate", challenge)
+	}
+	c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
+		Error:   "unauthorized",
+		Message: message,
+		Code:    http.StatusUnauthorized,
+	})
+}
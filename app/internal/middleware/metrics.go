@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/metrics"
+)
+
+// Metrics returns a gin middleware that records Prometheus request metrics
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		// Process request
+		c.Next()
+
+		// Use the route template to keep label cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).
+			Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// TenantKey is the gin context key holding the request's tenant
+const TenantKey = "tenant.id"
+
+// Tenant returns a gin middleware that scopes each request to a tenant: the
+// tenant the principal is bound to, else the X-Tenant-ID header if the
+// principal may choose its tenant, else defaultTenant. A header naming any
+// other tenant is rejected. It must run after authentication.
+func Tenant(defaultTenant string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, _ := auth.FromContext(c.Request.Context())
+		requested := c.GetHeader(tenant.Header)
+
+		if requested != "" && !tenant.Valid(requested) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, model.ErrorResponse{
+				Error:   "invalid_tenant",
+				Message: "Tenant IDs are lowercase letters, digits, '-' and '_'",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		id := principal.Tenant
+		switch {
+		case principal.CanChooseTenant() && requested != "":
+			id = requested
+		case id == "":
+			id = defaultTenant
+		}
+		if requested != "" && requested != id {
+			c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Credentials are bound to tenant " + id,
+				Code:    http.StatusForbidden,
+			})
+			return
+		}
+
+		c.Set(TenantKey, id)
+		c.Request = c.Request.WithContext(tenant.WithID(c.Request.Context(), id))
+		c.Next()
+	}
+}
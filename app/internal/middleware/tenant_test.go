@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+func TestTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	admin := auth.Principal{Subject: "root", Role: auth.RoleAdmin, Scopes: []string{model.ScopeAdmin}}
+	member := auth.Principal{Subject: "alice", Role: auth.RoleMember, Scopes: []string{model.ScopeTasksWrite}}
+	bound := auth.Principal{Subject: "bob", Role: auth.RoleAdmin, Scopes: []string{model.ScopeAdmin}, Tenant: "acme"}
+	readOnlyAdmin := auth.Principal{Subject: "audit", Role: auth.RoleAdmin, Scopes: []string{model.ScopeTasksRead}}
+
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		header    string
+		status    int
+		tenant    string
+	}{
+		{"unbound admin picks a tenant", admin, "globex", http.StatusOK, "globex"},
+		{"unbound admin defaults", admin, "", http.StatusOK, "default"},
+		{"anonymous picks a tenant", auth.Anonymous, "globex", http.StatusOK, "globex"},
+		{"unbound member stays in the default tenant", member, "", http.StatusOK, "default"},
+		{"unbound member cannot pick a tenant", member, "globex", http.StatusForbidden, ""},
+		{"unbound member may name the default tenant", member, "default", http.StatusOK, "default"},
+		{"admin without the admin scope cannot pick", readOnlyAdmin, "globex", http.StatusForbidden, ""},
+		{"bound principal uses its tenant", bound, "", http.StatusOK, "acme"},
+		{"bound principal may name its tenant", bound, "acme", http.StatusOK, "acme"},
+		{"bound principal cannot switch", bound, "globex", http.StatusForbidden, ""},
+		{"malformed tenant", admin, "Globex Corp", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scoped string
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), tt.principal))
+			}, Tenant("default"))
+			r.GET("/", func(c *gin.Context) {
+				scoped, _ = tenant.FromContext(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tenant.Header, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.status, w.Body.String())
+			}
+			if scoped != tt.tenant {
+				t.Fatalf("tenant = %q, want %q", scoped, tt.tenant)
+			}
+		})
+	}
+}
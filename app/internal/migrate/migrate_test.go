@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hamfa/task-manager/migrations"
+)
+
+func TestLoadOrdersByVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		versions []int64
+		err      string
+	}{
+		{
+			name:     "numeric rather than lexical order",
+			files:    []string{"10_ten.up.sql", "2_two.up.sql", "1_one.up.sql", "9_nine.up.sql"},
+			versions: []int64{1, 2, 9, 10},
+		},
+		{
+			name:     "up and down files pair up",
+			files:    []string{"002_b.down.sql", "001_a.up.sql", "002_b.up.sql", "001_a.down.sql"},
+			versions: []int64{1, 2},
+		},
+		{
+			name:     "other files are ignored",
+			files:    []string{"001_a.up.sql", "README.md", "002_b.sql", "migrations.go"},
+			versions: []int64{1},
+		},
+		{
+			name:  "down without up",
+			files: []string{"001_a.up.sql", "002_b.down.sql"},
+			err:   "migration 2_b has no up file",
+		},
+		{
+			name:  "conflicting names",
+			files: []string{"001_a.up.sql", "001_b.down.sql"},
+			err:   "conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for _, name := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte("-- " + name)}
+			}
+
+			got, err := load(fsys)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("load() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("load() error = %v", err)
+			}
+
+			var versions []int64
+			for _, m := range got {
+				versions = append(versions, m.Version)
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Fatalf("versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestLoadPairsBodies(t *testing.T) {
+	got, err := load(fstest.MapFS{
+		"001_create.up.sql":   {Data: []byte("CREATE TABLE t ()")},
+		"001_create.down.sql": {Data: []byte("DROP TABLE t")},
+	})
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	want := Migration{Version: 1, Name: "create", Up: "CREATE TABLE t ()", Down: "DROP TABLE t"}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("load() = %+v, want [%+v]", got, want)
+	}
+}
+
+// The shipped migrations must number 1, 2, 3, ... and be reversible
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := load(migrations.FS)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s at position %d, want version %d", m.Version, m.Name, i, i+1)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %d_%s has no down file", m.Version, m.Name)
+		}
+	}
+}
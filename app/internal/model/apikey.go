@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+)
+
+// API scopes. ScopeAdmin implies every other scope.
+const (
+	ScopeTasksRead  = "tasks:read"
+	ScopeTasksWrite = "tasks:write"
+	ScopeAdmin      = "admin"
+)
+
+// APIKey is a credential for machine clients. Only a hash of the secret is
+// stored; Prefix identifies the key in listings and logs.
+type APIKey struct {
+	ID     string   `json:"id" db:"id"`
+	Name   string   `json:"name" db:"name"`
+	Prefix string   `json:"prefix" db:"prefix"`
+	Scopes []string `json:"scopes" db:"scopes"`
+	// TenantID binds the key to one tenant; keys without one may pick a
+	// tenant per request
+	TenantID   string     `json:"tenant_id,omitempty" db:"tenant_id"`
+	CreatedBy  string     `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// Active reports whether the key can authenticate at now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyCreateRequest represents a request to create an API key
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=tasks:read tasks:write admin"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyCreatedResponse returns a new key. Key is the full secret and is
+// never shown again.
+type APIKeyCreatedResponse struct {
+	Data APIKey `json:"data"`
+	Key  string `json:"key"`
+}
+
+// APIKeyResponse wraps a single API key
+type APIKeyResponse struct {
+	Data APIKey `json:"data"`
+}
+
+// APIKeyListResponse wraps a list of API keys
+type APIKeyListResponse struct {
+	Data []APIKey `json:"data"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Attachment describes a file attached to a task. The content lives in a
+// BlobStore under StorageKey; the metadata lives with the tasks.
+type Attachment struct {
+	ID          string `json:"id" db:"id"`
+	TenantID    string `json:"tenant_id" db:"tenant_id"`
+	TaskID      string `json:"task_id" db:"task_id"`
+	Filename    string `json:"filename" db:"filename"`
+	ContentType string `json:"content_type" db:"content_type"`
+	Size        int64  `json:"size" db:"size"`
+	// Checksum is the hex-encoded SHA-256 of the content
+	Checksum   string    `json:"checksum" db:"checksum"`
+	StorageKey string    `json:"-" db:"storage_key"`
+	UploadedBy string    `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// AttachmentResponse wraps a single attachment
+type AttachmentResponse struct {
+	Data Attachment `json:"data"`
+}
+
+// AttachmentListResponse wraps the attachments of a task, oldest first
+type AttachmentListResponse struct {
+	Data []Attachment `json:"data"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import "encoding/json"
+
+// Batch operation kinds
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// BatchOperation is one create, update or delete in a batch request.
+// Task holds a TaskCreateRequest or TaskUpdateRequest depending on Op.
+type BatchOperation struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id,omitempty"`
+	Version int             `json:"version,omitempty"`
+	Task    json.RawMessage `json:"task,omitempty"`
+}
+
+// BatchRequest represents a POST /api/tasks:batch request.
+// With Atomic set, either every operation applies or none does.
+type BatchRequest struct {
+	Atomic     bool             `json:"atomic"`
+	Operations []BatchOperation `json:"operations" binding:"required,min=1,max=500"`
+}
+
+// BatchResult is the outcome of one batch operation
+type BatchResult struct {
+	Index  int            `json:"index"`
+	Op     string         `json:"op"`
+	ID     string         `json:"id,omitempty"`
+	Status int            `json:"status"`
+	Data   *Task          `json:"data,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+// BatchResponse wraps the per-operation results of a batch request
+type BatchResponse struct {
+	Atomic    bool          `json:"atomic"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// Comment is a message on a task, stored in MongoDB. Threads are one level
+// deep: a reply to a reply joins the thread of the top-level comment.
+type Comment struct {
+	ID       string `json:"id" bson:"_id"`
+	TenantID string `json:"tenant_id" bson:"tenant_id"`
+	TaskID   string `json:"task_id" bson:"task_id"`
+	// ParentID is the top-level comment a reply belongs to
+	ParentID string `json:"parent_id,omitempty" bson:"parent_id,omitempty"`
+	Author   string `json:"author" bson:"author"`
+	Body     string `json:"body" bson:"body"`
+	// History holds the previous bodies of an edited comment, oldest first
+	History    []CommentRevision `json:"history,omitempty" bson:"history,omitempty"`
+	ReplyCount int               `json:"reply_count" bson:"reply_count"`
+	// Deleted marks a top-level comment removed while it still had replies;
+	// its body and history are cleared
+	Deleted   bool      `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// CommentRevision is a previous body of a comment, replaced by an edit
+type CommentRevision struct {
+	Body      string    `json:"body" bson:"body"`
+	WrittenAt time.Time `json:"written_at" bson:"written_at"`
+}
+
+// CommentCreateRequest represents a POST /api/tasks/:id/comments request
+type CommentCreateRequest struct {
+	Body     string `json:"body" binding:"required,max=10000"`
+	ParentID string `json:"parent_id"`
+}
+
+// CommentUpdateRequest represents a PUT /api/tasks/:id/comments/:comment_id request
+type CommentUpdateRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// CommentResponse wraps a single comment
+type CommentResponse struct {
+	Data Comment `json:"data"`
+}
+
+// CommentListResponse wraps a page of comments, oldest first
+type CommentListResponse struct {
+	Data       []Comment `json:"data"`
+	PerPage    int       `json:"per_page"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// CommentCursor marks a position in the (created_at, id) keyset of a comment
+// list. Clients treat the encoded form as opaque.
+type CommentCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+// Encode returns the opaque, URL-safe form of the cursor
+func (c CommentCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCommentCursor parses a cursor produced by CommentCursor.Encode
+func DecodeCommentCursor(s string) (*CommentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c CommentCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// CommentListQuery selects the top-level comments of a task, or the replies
+// to ParentID, after Cursor
+type CommentListQuery struct {
+	TaskID   string
+	ParentID string
+	PerPage  int
+	Cursor   *CommentCursor
+}
+
+// CommentPage is one page of comments returned by a CommentStore
+type CommentPage struct {
+	Comments []Comment
+	// HasMore reports whether comments exist beyond this page
+	HasMore bool
+}
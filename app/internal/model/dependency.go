@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// MaxExecutionOrderTasks limits how many tasks one execution order request may sort
+const MaxExecutionOrderTasks = 100
+
+// TaskDependency records that TaskID is blocked by BlockerID: it cannot be
+// started or completed while BlockerID is open
+type TaskDependency struct {
+	TaskID    string    `json:"task_id"`
+	BlockerID string    `json:"blocker_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BlockerRequest represents a POST /api/tasks/:id/blockers request
+type BlockerRequest struct {
+	BlockerID string `json:"blocker_id" binding:"required"`
+}
+
+// DependencyResponse wraps a single dependency
+type DependencyResponse struct {
+	Data TaskDependency `json:"data"`
+}
+
+// BlockerListResponse wraps the tasks blocking a task
+type BlockerListResponse struct {
+	Data []Task `json:"data"`
+}
+
+// ExecutionOrderRequest represents a POST /api/tasks:executionOrder request
+type ExecutionOrderRequest struct {
+	IDs []string `json:"ids" binding:"required,min=1,max=100,dive,required"`
+}
+
+// ExecutionOrderResponse lists tasks so that every task follows its
+// blockers. Stages groups the task IDs into steps that only depend on
+// earlier steps; the tasks of one step can run in parallel.
+type ExecutionOrderResponse struct {
+	Data   []Task     `json:"data"`
+	Stages [][]string `json:"stages"`
+}
+
+// NeedsUnblocked reports whether moving a task to status requires every
+// blocker of the task to be closed
+func NeedsUnblocked(status string) bool {
+	return status == "in_progress" || status == "completed"
+}
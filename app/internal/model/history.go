@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// HistoryActions are the activity actions that make up a task's change
+// history, starting with its creation
+var HistoryActions = []string{"created", "updated", "reverted"}
+
+// FieldChange is the value of one task field before and after an update.
+// Values are stored as text: timestamps in RFC 3339, tags comma-separated. A
+// nil value means the field was not set.
+type FieldChange struct {
+	Field  string  `json:"field" bson:"field"`
+	Before *string `json:"before" bson:"before"`
+	After  *string `json:"after" bson:"after"`
+	// Diff is a unified diff from Before to After; only the history view
+	// fills it in, and only for descriptions
+	Diff string `json:"diff,omitempty" bson:"-"`
+}
+
+// TaskHistoryResponse wraps the change history of a task, newest first.
+// Entries logged before field changes were recorded have no changes and
+// only carry their details.
+type TaskHistoryResponse struct {
+	Data []ActivityLog `json:"data"`
+}
+
+// DiffTaskFields returns the fields that differ between two versions of a
+// task, in a fixed order
+func DiffTaskFields(before, after Task) []FieldChange {
+	var changes []FieldChange
+	add := func(field string, b, a *string) {
+		if (b == nil) != (a == nil) || (b != nil && *b != *a) {
+			changes = append(changes, FieldChange{Field: field, Before: b, After: a})
+		}
+	}
+
+	add("title", &before.Title, &after.Title)
+	add("description", &before.Description, &after.Description)
+	add("status", &before.Status, &after.Status)
+	add("priority", &before.Priority, &after.Priority)
+	add("start_at", formatTime(before.StartAt), formatTime(after.StartAt))
+	add("due_at", formatTime(before.DueAt), formatTime(after.DueAt))
+	add("assignee_id", before.AssigneeID, after.AssigneeID)
+	add("reporter_id", before.ReporterID, after.ReporterID)
+	add("project_id", before.ProjectID, after.ProjectID)
+	add("parent_id", before.ParentID, after.ParentID)
+	add("tags", joinTags(before.Tags), joinTags(after.Tags))
+	return changes
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339Nano)
+	return &s
+}
+
+func joinTags(tags []string) *string {
+	if len(tags) == 0 {
+		return nil
+	}
+	s := strings.Join(tags, ",")
+	return &s
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffTaskFields(t *testing.T) {
+	due := time.Date(2024, 5, 1, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	alice, bob := "alice", "bob"
+	base := Task{
+		ID:          "1",
+		Title:       "Write report",
+		Description: "Q1 numbers",
+		Status:      "pending",
+		Priority:    "medium",
+		DueAt:       &due,
+		AssigneeID:  &alice,
+		Tags:        []string{"finance"},
+		Version:     3,
+	}
+
+	tests := []struct {
+		name   string
+		change func(t *Task)
+		want   []FieldChange
+	}{
+		{
+			name:   "no change",
+			change: func(t *Task) {},
+		},
+		{
+			name:   "bookkeeping fields are ignored",
+			change: func(t *Task) { t.Version++; t.UpdatedAt = time.Now() },
+		},
+		{
+			name:   "same instant in another zone",
+			change: func(t *Task) { d := due.UTC(); t.DueAt = &d },
+		},
+		{
+			name:   "fields in fixed order",
+			change: func(t *Task) { t.Priority, t.Title = "high", "Write the report" },
+			want: []FieldChange{
+				{Field: "title", Before: ptr("Write report"), After: ptr("Write the report")},
+				{Field: "priority", Before: ptr("medium"), After: ptr("high")},
+			},
+		},
+		{
+			name:   "cleared and set references",
+			change: func(t *Task) { t.AssigneeID, t.ReporterID = nil, &bob },
+			want: []FieldChange{
+				{Field: "assignee_id", Before: ptr("alice"), After: nil},
+				{Field: "reporter_id", Before: nil, After: ptr("bob")},
+			},
+		},
+		{
+			name:   "times in UTC",
+			change: func(t *Task) { d := due.Add(24 * time.Hour); t.DueAt = &d },
+			want: []FieldChange{
+				{Field: "due_at", Before: ptr("2024-05-01T07:00:00Z"), After: ptr("2024-05-02T07:00:00Z")},
+			},
+		},
+		{
+			name:   "tags comma-separated, empty as unset",
+			change: func(t *Task) { t.Tags = []string{} },
+			want: []FieldChange{
+				{Field: "tags", Before: ptr("finance"), After: nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base
+			tt.change(&after)
+
+			got := DiffTaskFields(base, after)
+			if len(got) != len(tt.want) {
+				t.Fatalf("DiffTaskFields() = %s, want %s", formatChanges(got), formatChanges(tt.want))
+			}
+			for i := range got {
+				if formatChanges(got[i:i+1]) != formatChanges(tt.want[i:i+1]) {
+					t.Errorf("change %d = %s, want %s", i, formatChanges(got[i:i+1]), formatChanges(tt.want[i:i+1]))
+				}
+			}
+		})
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+// formatChanges renders changes with their values rather than pointers
+func formatChanges(changes []FieldChange) string {
+	value := func(s *string) string {
+		if s == nil {
+			return "<nil>"
+		}
+		return "\"" + *s + "\""
+	}
+	out := "["
+	for i, c := range changes {
+		if i > 0 {
+			out += " "
+		}
+		out += c.Field + ":" + value(c.Before) + "->" + value(c.After)
+	}
+	return out + "]"
+}
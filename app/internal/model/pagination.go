@@ -0,0 +1,247 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Pagination defaults for list endpoints
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// Sort fields accepted by the task list
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortPriority  = "priority"
+	SortTitle     = "title"
+	// SortRelevance orders full-text search results by rank
+	SortRelevance = "relevance"
+)
+
+// Sort orders accepted by the task list
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskCursor marks a position in the (sort key, id) keyset of the task list.
+// Clients treat the encoded form as opaque.
+type TaskCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"i"`
+	// Backward pages towards the start of the list (the previous page)
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the opaque, URL-safe form of the cursor
+func (c TaskCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTaskCursor parses a cursor produced by TaskCursor.Encode
+func DecodeTaskCursor(s string) (*TaskCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c TaskCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := c.Pivot(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Pivot returns a task carrying the cursor's id and sort key, for comparisons
+func (c TaskCursor) Pivot() (Task, error) {
+	t := Task{ID: c.ID}
+
+	switch c.Sort {
+	case SortCreatedAt, SortUpdatedAt:
+		ts, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return t, ErrInvalidCursor
+		}
+		if c.Sort == SortCreatedAt {
+			t.CreatedAt = ts
+		} else {
+			t.UpdatedAt = ts
+		}
+	case SortPriority:
+		rank, err := strconv.Atoi(c.Value)
+		if err != nil || priorityByRank(rank) == "" {
+			return t, ErrInvalidCursor
+		}
+		t.Priority = priorityByRank(rank)
+	case SortTitle:
+		t.Title = c.Value
+	case SortRelevance:
+		rank, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return t, ErrInvalidCursor
+		}
+		t.Rank = rank
+	default:
+		return t, ErrInvalidCursor
+	}
+
+	return t, nil
+}
+
+// TaskFilter narrows a task listing. Empty fields do not filter.
+type TaskFilter struct {
+	// IDs limits the list to the given tasks; it is not exposed as a query
+	// parameter
+	IDs []string
+	// Search is a full-text query over title and description
+	Search        string
+	Statuses      []string
+	Priorities    []string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	DueAfter      *time.Time
+	DueBefore     *time.Time
+	AssigneeIDs   []string
+	ReporterIDs   []string
+	ProjectIDs    []string
+	ParentIDs     []string
+	// TagsAny matches tasks with at least one of the tags, TagsAll those
+	// carrying every one of them
+	TagsAny []string
+	TagsAll []string
+	// OpenOnly excludes ClosedStatuses; set by the overdue and due_within filters
+	OpenOnly bool
+	// Trashed lists the tasks in the trash instead of the live ones; set by
+	// GET /api/tasks/trash
+	Trashed bool
+}
+
+// TaskListQuery holds the parameters of a task listing.
+// When Cursor is set the list is keyset-paginated and Page is ignored.
+type TaskListQuery struct {
+	Page         int
+	PerPage      int
+	Cursor       *TaskCursor
+	IncludeTotal bool
+	Filter       TaskFilter
+	Sort         string
+	Order        string
+}
+
+// Normalize clamps paging values to their allowed ranges and applies the
+// default created_at DESC ordering
+func (q *TaskListQuery) Normalize() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PerPage < 1 || q.PerPage > MaxPerPage {
+		q.PerPage = DefaultPerPage
+	}
+	if q.Sort == "" {
+		q.Sort = SortCreatedAt
+		if q.Filter.Search != "" {
+			q.Sort = SortRelevance
+		}
+	}
+	if q.Order == "" {
+		q.Order = OrderDesc
+	}
+}
+
+// Validate checks filter values, sort options and cursor consistency
+func (q *TaskListQuery) Validate() error {
+	for _, s := range q.Filter.Statuses {
+		if !IsValidStatus(s) {
+			return fmt.Errorf("invalid status %q", s)
+		}
+	}
+	for _, p := range q.Filter.Priorities {
+		if PriorityRank(p) == 0 {
+			return fmt.Errorf("invalid priority %q", p)
+		}
+	}
+
+	switch q.Sort {
+	case SortCreatedAt, SortUpdatedAt, SortPriority, SortTitle:
+	case SortRelevance:
+		if q.Filter.Search == "" {
+			return fmt.Errorf("sort=relevance requires a search query (q)")
+		}
+	default:
+		return fmt.Errorf("invalid sort %q: must be one of created_at, updated_at, priority, title, relevance", q.Sort)
+	}
+	if q.Order != OrderAsc && q.Order != OrderDesc {
+		return fmt.Errorf("invalid order %q: must be asc or desc", q.Order)
+	}
+
+	f := q.Filter
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return fmt.Errorf("created_after must be before created_before")
+	}
+	if f.UpdatedAfter != nil && f.UpdatedBefore != nil && !f.UpdatedAfter.Before(*f.UpdatedBefore) {
+		return fmt.Errorf("updated_after must be before updated_before")
+	}
+	if f.DueAfter != nil && f.DueBefore != nil && !f.DueAfter.Before(*f.DueBefore) {
+		return fmt.Errorf("due_after must be before due_before")
+	}
+
+	if q.Cursor != nil && (q.Cursor.Sort != q.Sort || q.Cursor.Order != q.Order) {
+		return fmt.Errorf("cursor was issued for a different sort order")
+	}
+
+	return nil
+}
+
+// Offset returns the row offset for page-based pagination
+func (q *TaskListQuery) Offset() int {
+	if q.Cursor != nil {
+		return 0
+	}
+	return (q.Page - 1) * q.PerPage
+}
+
+// CursorFor returns a cursor positioned at t in this query's ordering
+func (q *TaskListQuery) CursorFor(t Task, backward bool) TaskCursor {
+	var value string
+	switch q.Sort {
+	case SortUpdatedAt:
+		value = t.UpdatedAt.Format(time.RFC3339Nano)
+	case SortPriority:
+		value = strconv.Itoa(PriorityRank(t.Priority))
+	case SortTitle:
+		value = t.Title
+	case SortRelevance:
+		value = strconv.FormatFloat(t.Rank, 'g', -1, 64)
+	default:
+		value = t.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return TaskCursor{Sort: q.Sort, Order: q.Order, Value: value, ID: t.ID, Backward: backward}
+}
+
+// TaskPage is one page of tasks returned by a TaskStore
+type TaskPage struct {
+	Tasks []Task
+	// Total is only populated when the query asked for it
+	Total *int
+	// HasMore reports whether rows exist beyond this page in the scan direction
+	HasMore bool
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTaskCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	task := Task{
+		ID:        "7b1e",
+		Title:     "Ship it, then \"celebrate\"",
+		Priority:  "high",
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+		Rank:      0.0607927,
+	}
+
+	tests := []struct {
+		sort  string
+		order string
+		check func(Task) bool
+	}{
+		{SortCreatedAt, OrderDesc, func(p Task) bool { return p.CreatedAt.Equal(task.CreatedAt) }},
+		{SortUpdatedAt, OrderAsc, func(p Task) bool { return p.UpdatedAt.Equal(task.UpdatedAt) }},
+		{SortPriority, OrderDesc, func(p Task) bool { return p.Priority == task.Priority }},
+		{SortTitle, OrderAsc, func(p Task) bool { return p.Title == task.Title }},
+		{SortRelevance, OrderDesc, func(p Task) bool { return p.Rank == task.Rank }},
+	}
+
+	for _, tt := range tests {
+		for _, backward := range []bool{false, true} {
+			q := TaskListQuery{Sort: tt.sort, Order: tt.order}
+			cursor := q.CursorFor(task, backward)
+
+			got, err := DecodeTaskCursor(cursor.Encode())
+			if err != nil {
+				t.Fatalf("%s: DecodeTaskCursor() error = %v", tt.sort, err)
+			}
+			if *got != cursor {
+				t.Errorf("%s: decoded %+v, want %+v", tt.sort, *got, cursor)
+			}
+
+			pivot, err := got.Pivot()
+			if err != nil {
+				t.Fatalf("%s: Pivot() error = %v", tt.sort, err)
+			}
+			if pivot.ID != task.ID || !tt.check(pivot) {
+				t.Errorf("%s: pivot %+v does not carry the sort key of %+v", tt.sort, pivot, task)
+			}
+		}
+	}
+}
+
+func TestDecodeTaskCursorRejectsInvalid(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"not json", encode("created_at")},
+		{"missing id", encode(`{"s":"created_at","o":"desc","v":"2024-03-01T12:00:00Z"}`)},
+		{"unknown sort", encode(`{"s":"status","o":"desc","v":"pending","i":"1"}`)},
+		{"bad timestamp", encode(`{"s":"created_at","o":"desc","v":"yesterday","i":"1"}`)},
+		{"priority out of range", encode(`{"s":"priority","o":"desc","v":"9","i":"1"}`)},
+		{"priority by name", encode(`{"s":"priority","o":"desc","v":"high","i":"1"}`)},
+		{"bad rank", encode(`{"s":"relevance","o":"desc","v":"high","i":"1"}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeTaskCursor(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeTaskCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsCursorOfOtherOrdering(t *testing.T) {
+	issued := TaskListQuery{Sort: SortTitle, Order: OrderAsc}
+	cursor := issued.CursorFor(Task{ID: "1", Title: "a"}, false)
+
+	tests := []struct {
+		sort, order string
+		ok          bool
+	}{
+		{SortTitle, OrderAsc, true},
+		{SortTitle, OrderDesc, false},
+		{SortCreatedAt, OrderAsc, false},
+	}
+	for _, tt := range tests {
+		q := TaskListQuery{Sort: tt.sort, Order: tt.order, Cursor: &cursor}
+		if err := q.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate() with sort=%s order=%s: error = %v, want ok=%v", tt.sort, tt.order, err, tt.ok)
+		}
+	}
+}
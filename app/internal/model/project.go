@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+)
+
+// Project groups related tasks of a tenant
+type Project struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	Name        string    `json:"name" db:"name"`
+	Description string    `json:"description" db:"description"`
+	OwnerID     *string   `json:"owner_id,omitempty" db:"owner_id"`
+	Archived    bool      `json:"archived" db:"archived"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectCreateRequest represents a request to create a project
+type ProjectCreateRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=255"`
+	Description string  `json:"description"`
+	OwnerID     *string `json:"owner_id"`
+}
+
+// ProjectUpdateRequest represents a request to update a project. An empty
+// OwnerID clears the owner.
+type ProjectUpdateRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+	OwnerID     *string `json:"owner_id"`
+	Archived    *bool   `json:"archived"`
+}
+
+// ProjectSummary rolls up the tasks of a project. PercentComplete is the
+// share of completed tasks among those not cancelled, from 0 to 100.
+type ProjectSummary struct {
+	ProjectID       string         `json:"project_id"`
+	Total           int            `json:"total"`
+	ByStatus        map[string]int `json:"by_status"`
+	ByPriority      map[string]int `json:"by_priority"`
+	PercentComplete float64        `json:"percent_complete"`
+}
+
+// FillZeroCounts adds every known status and priority missing from the
+// summary with a count of 0
+func (s *ProjectSummary) FillZeroCounts() {
+	if s.ByStatus == nil {
+		s.ByStatus = make(map[string]int)
+	}
+	if s.ByPriority == nil {
+		s.ByPriority = make(map[string]int)
+	}
+	for _, status := range TaskStatuses {
+		s.ByStatus[status] += 0
+	}
+	for _, priority := range taskPriorities {
+		s.ByPriority[priority] += 0
+	}
+}
+
+// ProjectResponse wraps a single project response
+type ProjectResponse struct {
+	Data Project `json:"data"`
+}
+
+// ProjectListResponse wraps a page of projects
+type ProjectListResponse struct {
+	Data    []Project `json:"data"`
+	Total   int       `json:"total"`
+	Page    int       `json:"page"`
+	PerPage int       `json:"per_page"`
+}
+
+// ProjectSummaryResponse wraps a project summary
+type ProjectSummaryResponse struct {
+	Data ProjectSummary `json:"data"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// Reminder kinds; each is also the action of the activity entry it produces
+const (
+	// ReminderDueSoon fires once an open task's due date is within the reminder window
+	ReminderDueSoon = "due_soon"
+	// ReminderOverdue fires once an open task's due date has passed
+	ReminderOverdue = "overdue"
+)
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// What happens to the subtasks of a deleted task
+const (
+	// SubtasksReject refuses to delete a task that has subtasks
+	SubtasksReject = "reject"
+	// SubtasksCascade deletes every subtask, at any depth, with the task
+	SubtasksCascade = "cascade"
+	// SubtasksOrphan detaches the direct subtasks, which become top-level tasks
+	SubtasksOrphan = "orphan"
+)
+
+// MaxTaskDepth limits how many ancestors a task may have
+const MaxTaskDepth = 10
+
+// SubtaskRules configures how parent tasks and their subtasks interact
+type SubtaskRules struct {
+	// CompleteWithOpenSubtasks allows completing a task while some of its
+	// subtasks are neither completed nor cancelled
+	CompleteWithOpenSubtasks bool
+	// OnDelete is SubtasksReject, SubtasksCascade or SubtasksOrphan
+	OnDelete string
+}
+
+// Validate checks that OnDelete names a known policy
+func (r SubtaskRules) Validate() error {
+	switch r.OnDelete {
+	case SubtasksReject, SubtasksCascade, SubtasksOrphan:
+		return nil
+	}
+	return fmt.Errorf("unknown subtask delete policy %q: must be reject, cascade or orphan", r.OnDelete)
+}
+
+// TaskProgress rolls up the subtasks of a task at every depth.
+// PercentComplete is the share of completed subtasks among those not
+// cancelled, from 0 to 100.
+type TaskProgress struct {
+	Subtasks        int     `json:"subtasks"`
+	Completed       int     `json:"completed"`
+	Cancelled       int     `json:"cancelled"`
+	PercentComplete float64 `json:"percent_complete"`
+}
+
+// Add counts one subtask with the given status
+func (p *TaskProgress) Add(status string) {
+	p.Subtasks++
+	switch status {
+	case "completed":
+		p.Completed++
+	case "cancelled":
+		p.Cancelled++
+	}
+	p.updatePercent()
+}
+
+// Merge adds the counts of another rollup
+func (p *TaskProgress) Merge(other TaskProgress) {
+	p.Subtasks += other.Subtasks
+	p.Completed += other.Completed
+	p.Cancelled += other.Cancelled
+	p.updatePercent()
+}
+
+// Open returns the number of subtasks that are neither completed nor cancelled
+func (p TaskProgress) Open() int {
+	return p.Subtasks - p.Completed - p.Cancelled
+}
+
+func (p *TaskProgress) updatePercent() {
+	p.PercentComplete = 0
+	if countable := p.Subtasks - p.Cancelled; countable > 0 {
+		p.PercentComplete = math.Round(1000*float64(p.Completed)/float64(countable)) / 10
+	}
+}
+
+// TaskNode is a task together with its subtasks
+type TaskNode struct {
+	Task
+	Children []TaskNode `json:"children"`
+}
+
+// TaskTreeResponse wraps a task tree
+type TaskTreeResponse struct {
+	Data TaskNode `json:"data"`
+}
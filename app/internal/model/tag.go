@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxTagsPerTask limits how many tags a single task may carry
+const MaxTagsPerTask = 20
+
+// tagPattern restricts tags to short lowercase slugs such as "k8s" or "on-call"
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,49}$`)
+
+// NormalizeTags lowercases and trims tags, drops duplicates and sorts them.
+// It rejects tags that are not slugs and lists longer than MaxTagsPerTask.
+func NormalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("invalid tag %q: use up to 50 lowercase letters, digits, '-', '_' or '.'", tag)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > MaxTagsPerTask {
+		return nil, fmt.Errorf("a task may have at most %d tags", MaxTagsPerTask)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// DiffTags returns the tags in after but not in before, and those in before
+// but not in after
+func DiffTags(before, after []string) (added, removed []string) {
+	in := func(tags []string, tag string) bool {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, tag := range after {
+		if !in(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	for _, tag := range before {
+		if !in(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+	return added, removed
+}
+
+// TagCount is the number of tasks carrying a tag
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TagListResponse wraps the tag usage counts of a tenant
+type TagListResponse struct {
+	Data []TagCount `json:"data"`
+}
@@ -6,20 +6,95 @@ import (
 
 // Task represents a task in the system
 type Task struct {
-	ID          string    `json:"id" db:"id"`
-	Title       string    `json:"title" db:"title" binding:"required,min=1,max=255"`
-	Description string    `json:"description" db:"description"`
-	Status      string    `json:"status" db:"status"`
-	Priority    string    `json:"priority" db:"priority"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          string     `json:"id" db:"id"`
+	TenantID    string     `json:"tenant_id" db:"tenant_id"`
+	Title       string     `json:"title" db:"title" binding:"required,min=1,max=255"`
+	Description string     `json:"description" db:"description"`
+	Status      string     `json:"status" db:"status"`
+	Priority    string     `json:"priority" db:"priority"`
+	StartAt     *time.Time `json:"start_at,omitempty" db:"start_at"`
+	DueAt       *time.Time `json:"due_at,omitempty" db:"due_at"`
+	AssigneeID  *string    `json:"assignee_id,omitempty" db:"assignee_id"`
+	ReporterID  *string    `json:"reporter_id,omitempty" db:"reporter_id"`
+	ProjectID   *string    `json:"project_id,omitempty" db:"project_id"`
+	ParentID    *string    `json:"parent_id,omitempty" db:"parent_id"`
+	Tags        []string   `json:"tags,omitempty" db:"-"`
+	Version     int        `json:"version" db:"version"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	// DeletedAt is set while the task is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// Search result fields, only set when listing with a full-text query
+	Rank    float64 `json:"rank,omitempty" db:"-"`
+	Snippet string  `json:"snippet,omitempty" db:"-"`
+
+	// Progress rolls up the subtasks; only set by the children and tree
+	// endpoints, for tasks that have subtasks
+	Progress *TaskProgress `json:"progress,omitempty" db:"-"`
+}
+
+// Task statuses
+var TaskStatuses = []string{"pending", "in_progress", "completed", "cancelled"}
+
+// ClosedStatuses are the statuses that no longer need work; due dates of
+// closed tasks are ignored
+var ClosedStatuses = []string{"completed", "cancelled"}
+
+// IsOpen reports whether the task still needs work
+func (t Task) IsOpen() bool {
+	return !containsStatus(ClosedStatuses, t.Status)
+}
+
+// IsOverdue reports whether an open task is past its due date at now
+func (t Task) IsOverdue(now time.Time) bool {
+	return t.IsOpen() && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+// taskPriorities lists priorities in ascending semantic order
+var taskPriorities = []string{"low", "medium", "high", "critical"}
+
+// IsValidStatus reports whether s is a known task status
+func IsValidStatus(s string) bool {
+	for _, status := range TaskStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// PriorityRank returns the semantic rank of a priority (low=1 … critical=4),
+// or 0 for an unknown priority
+func PriorityRank(p string) int {
+	for i, priority := range taskPriorities {
+		if p == priority {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// priorityByRank is the inverse of PriorityRank
+func priorityByRank(rank int) string {
+	if rank < 1 || rank > len(taskPriorities) {
+		return ""
+	}
+	return taskPriorities[rank-1]
 }
 
 // TaskCreateRequest represents a request to create a task
 type TaskCreateRequest struct {
-	Title       string `json:"title" binding:"required,min=1,max=255"`
-	Description string `json:"description"`
-	Priority    string `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	Title       string     `json:"title" binding:"required,min=1,max=255"`
+	Description string     `json:"description"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	AssigneeID  *string    `json:"assignee_id"`
+	ReporterID  *string    `json:"reporter_id"`
+	ProjectID   *string    `json:"project_id"`
+	ParentID    *string    `json:"parent_id"`
+	Tags        []string   `json:"tags"`
 }
 
 // TaskUpdateRequest represents a request to update a task
@@ -28,6 +103,83 @@ type TaskUpdateRequest struct {
 	Description *string `json:"description"`
 	Status      *string `json:"status" binding:"omitempty,oneof=pending in_progress completed cancelled"`
 	Priority    *string `json:"priority" binding:"omitempty,oneof=low medium high critical"`
+	// StartAt and DueAt can be set but not cleared here; use PATCH to clear them
+	StartAt *time.Time `json:"start_at"`
+	DueAt   *time.Time `json:"due_at"`
+	// An empty AssigneeID, ReporterID, ProjectID or ParentID clears the reference
+	AssigneeID *string `json:"assignee_id"`
+	ReporterID *string `json:"reporter_id"`
+	ProjectID  *string `json:"project_id"`
+	ParentID   *string `json:"parent_id"`
+	// Tags replaces every tag of the task; an empty list removes them all
+	Tags *[]string `json:"tags"`
+}
+
+// ApplyTo copies the fields set in the request onto t
+func (r TaskUpdateRequest) ApplyTo(t *Task) {
+	if r.Title != nil {
+		t.Title = *r.Title
+	}
+	if r.Description != nil {
+		t.Description = *r.Description
+	}
+	if r.Status != nil {
+		t.Status = *r.Status
+	}
+	if r.Priority != nil {
+		t.Priority = *r.Priority
+	}
+	if r.StartAt != nil {
+		t.StartAt = r.StartAt
+	}
+	if r.DueAt != nil {
+		t.DueAt = r.DueAt
+	}
+	if r.AssigneeID != nil {
+		t.AssigneeID = optionalString(*r.AssigneeID)
+	}
+	if r.ReporterID != nil {
+		t.ReporterID = optionalString(*r.ReporterID)
+	}
+	if r.ProjectID != nil {
+		t.ProjectID = optionalString(*r.ProjectID)
+	}
+	if r.ParentID != nil {
+		t.ParentID = optionalString(*r.ParentID)
+	}
+	if r.Tags != nil {
+		t.Tags = *r.Tags
+	}
+}
+
+// optionalString maps "" to nil, so an empty ID in a request clears the reference
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// Patch content types accepted by PATCH /api/tasks/:id
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// TaskPatchDocument is the JSON view of a task that PATCH requests operate on.
+// Removing a member (or setting it to null in a merge patch) clears it.
+type TaskPatchDocument struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at"`
+	AssigneeID  *string    `json:"assignee_id"`
+	ReporterID  *string    `json:"reporter_id"`
+	ProjectID   *string    `json:"project_id"`
+	ParentID    *string    `json:"parent_id"`
+	Tags        []string   `json:"tags"`
 }
 
 // TaskResponse wraps a single task response
@@ -35,21 +187,35 @@ type TaskResponse struct {
 	Data Task `json:"data"`
 }
 
-// TaskListResponse wraps a list of tasks
+// TaskListResponse wraps a list of tasks.
+// Page is omitted for cursor-paginated requests; Total is omitted when
+// include_total=false.
 type TaskListResponse struct {
 	Data       []Task `json:"data"`
-	Total      int    `json:"total"`
-	Page       int    `json:"page"`
+	Total      *int   `json:"total,omitempty"`
+	Page       int    `json:"page,omitempty"`
 	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
-// ActivityLog represents an activity log entry stored in MongoDB
+// ActivityLog represents an activity log entry stored in MongoDB. "created"
+// and "updated" entries also carry the task version they produced, and
+// "updated" entries the fields they changed; "reverted" entries carry the version reverted to and, in From, the
+// ID of the activity that produced it.
 type ActivityLog struct {
-	ID        string    `json:"id" bson:"_id,omitempty"`
-	TaskID    string    `json:"task_id" bson:"task_id"`
-	Action    string    `json:"action" bson:"action"`
-	Details   string    `json:"details" bson:"details"`
-	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	ID        string        `json:"id" bson:"_id,omitempty"`
+	TenantID  string        `json:"tenant_id,omitempty" bson:"tenant_id"`
+	TaskID    string        `json:"task_id" bson:"task_id"`
+	Action    string        `json:"action" bson:"action"`
+	Details   string        `json:"details" bson:"details"`
+	From      string        `json:"from,omitempty" bson:"from,omitempty"`
+	To        string        `json:"to,omitempty" bson:"to,omitempty"`
+	Actor     string        `json:"actor,omitempty" bson:"actor,omitempty"`
+	CommentID string        `json:"comment_id,omitempty" bson:"comment_id,omitempty"`
+	Version   int           `json:"version,omitempty" bson:"version,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty" bson:"changes,omitempty"`
+	Timestamp time.Time     `json:"timestamp" bson:"timestamp"`
 }
 
 // HealthResponse represents the health check response
@@ -61,7 +227,8 @@ type HealthResponse struct {
 
 // ErrorResponse represents an error response
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Error   string      `json:"error"`
+	Message string      `json:"message"`
+	Code    int         `json:"code"`
+	Details interface{} `json:"details,omitempty"`
 }
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+)
+
+// User is a person tasks of the same tenant can be assigned to or reported by
+type User struct {
+	ID          string    `json:"id" db:"id"`
+	TenantID    string    `json:"tenant_id" db:"tenant_id"`
+	Username    string    `json:"username" db:"username"`
+	Email       string    `json:"email" db:"email"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UserCreateRequest represents a request to create a user
+type UserCreateRequest struct {
+	Username    string `json:"username" binding:"required,min=3,max=50,alphanum"`
+	Email       string `json:"email" binding:"required,email,max=255"`
+	DisplayName string `json:"display_name" binding:"max=255"`
+}
+
+// UserUpdateRequest represents a request to update a user
+type UserUpdateRequest struct {
+	Username    *string `json:"username" binding:"omitempty,min=3,max=50,alphanum"`
+	Email       *string `json:"email" binding:"omitempty,email,max=255"`
+	DisplayName *string `json:"display_name" binding:"omitempty,max=255"`
+}
+
+// UserResponse wraps a single user response
+type UserResponse struct {
+	Data User `json:"data"`
+}
+
+// UserListResponse wraps a page of users
+type UserListResponse struct {
+	Data    []User `json:"data"`
+	Total   int    `json:"total"`
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+}
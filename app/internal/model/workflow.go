@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Workflow maps each task status to the statuses it may move to.
+// A status without an entry is terminal.
+type Workflow map[string][]string
+
+// DefaultWorkflow returns the built-in status workflow
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		"pending":     {"in_progress", "cancelled"},
+		"in_progress": {"pending", "completed", "cancelled"},
+		"completed":   {"in_progress"},
+		"cancelled":   {"pending"},
+	}
+}
+
+// ParseWorkflow builds a workflow from TASK_WORKFLOW, e.g.
+// "pending:in_progress|cancelled,in_progress:completed". An empty spec
+// yields DefaultWorkflow.
+func ParseWorkflow(spec map[string]string) (Workflow, error) {
+	if len(spec) == 0 {
+		return DefaultWorkflow(), nil
+	}
+
+	w := make(Workflow, len(spec))
+	for from, targets := range spec {
+		if !IsValidStatus(from) {
+			return nil, fmt.Errorf("workflow: unknown status %q", from)
+		}
+		for _, to := range strings.Split(targets, "|") {
+			to = strings.TrimSpace(to)
+			if to == "" {
+				continue
+			}
+			if !IsValidStatus(to) {
+				return nil, fmt.Errorf("workflow: unknown status %q", to)
+			}
+			w[from] = append(w[from], to)
+		}
+	}
+
+	return w, nil
+}
+
+// Allowed returns the statuses a task in status from may move to
+func (w Workflow) Allowed(from string) []string {
+	allowed := w[from]
+	if allowed == nil {
+		return []string{}
+	}
+	return allowed
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string) bool {
+	return from == to || containsStatus(w[from], to)
+}
+
+func containsStatus(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseWorkflow(t *testing.T) {
+	tests := []struct {
+		name string
+		spec map[string]string
+		want Workflow
+		err  string
+	}{
+		{
+			name: "empty spec is the default workflow",
+			want: DefaultWorkflow(),
+		},
+		{
+			name: "targets split on pipes",
+			spec: map[string]string{"pending": "in_progress|cancelled", "in_progress": "completed"},
+			want: Workflow{"pending": {"in_progress", "cancelled"}, "in_progress": {"completed"}},
+		},
+		{
+			name: "blanks are ignored",
+			spec: map[string]string{"pending": " in_progress || completed ", "completed": ""},
+			want: Workflow{"pending": {"in_progress", "completed"}},
+		},
+		{
+			name: "unknown source status",
+			spec: map[string]string{"done": "pending"},
+			err:  `unknown status "done"`,
+		},
+		{
+			name: "unknown target status",
+			spec: map[string]string{"pending": "in_progress|blocked"},
+			err:  `unknown status "blocked"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWorkflow(tt.spec)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseWorkflow() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWorkflow() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseWorkflow() = %v, want %v", got, tt.want)
+			}
+			for from, targets := range tt.want {
+				if !slices.Equal(got[from], targets) {
+					t.Errorf("ParseWorkflow()[%q] = %v, want %v", from, got[from], targets)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowCanTransition(t *testing.T) {
+	custom, err := ParseWorkflow(map[string]string{"pending": "in_progress", "in_progress": "completed"})
+	if err != nil {
+		t.Fatalf("ParseWorkflow() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		workflow Workflow
+		from, to string
+		want     bool
+	}{
+		{"default: start work", DefaultWorkflow(), "pending", "in_progress", true},
+		{"default: cancel", DefaultWorkflow(), "pending", "cancelled", true},
+		{"default: skip to completed", DefaultWorkflow(), "pending", "completed", false},
+		{"default: reopen", DefaultWorkflow(), "completed", "in_progress", true},
+		{"default: revive cancelled", DefaultWorkflow(), "cancelled", "pending", true},
+		{"default: cancelled to completed", DefaultWorkflow(), "cancelled", "completed", false},
+		{"same status", DefaultWorkflow(), "completed", "completed", true},
+		{"custom: allowed", custom, "in_progress", "completed", true},
+		{"custom: not listed", custom, "pending", "cancelled", false},
+		{"custom: terminal status", custom, "completed", "in_progress", false},
+		{"custom: terminal stays", custom, "completed", "completed", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.workflow.CanTransition(tt.from, tt.to); got != tt.want {
+				t.Fatalf("CanTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowAllowedOfTerminalStatus(t *testing.T) {
+	w := Workflow{"pending": {"completed"}}
+	if got := w.Allowed("completed"); got == nil || len(got) != 0 {
+		t.Fatalf("Allowed(terminal) = %#v, want an empty, non-nil list", got)
+	}
+}
@@ -0,0 +1,36 @@
+package repository
+
+import "github.com/hamfa/task-manager/internal/model"
+
+// TaskBatchOp is one write in a TaskStore.Batch call
+type TaskBatchOp struct {
+	// Kind is model.BatchOpCreate, model.BatchOpUpdate or model.BatchOpDelete
+	Kind            string
+	ID              string
+	ExpectedVersion int
+	Create          model.TaskCreateRequest
+	Update          model.TaskUpdateRequest
+	// Modify, when set on an update op, is applied to the locked task in
+	// place of Update (see TaskStore.Modify)
+	Modify func(*model.Task) error
+	// OnSubtasks is the subtask policy of a delete op (see TaskStore.Delete)
+	OnSubtasks string
+}
+
+// TaskBatchResult is the outcome of one TaskBatchOp. Task is the created,
+// updated or deleted task; Subtasks are the subtasks a delete removed or
+// detached.
+type TaskBatchResult struct {
+	Task     *model.Task
+	Subtasks []model.Task
+	Err      error
+}
+
+// abortBatch marks the op at index failed with err and every other op as
+// rolled back
+func abortBatch(results []TaskBatchResult, failed int, err error) {
+	for i := range results {
+		results[i] = TaskBatchResult{Err: ErrBatchAborted}
+	}
+	results[failed].Err = err
+}
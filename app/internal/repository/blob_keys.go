@@ -0,0 +1,12 @@
+package repository
+
+// AttachmentKey returns the blob key of an attachment's content. Keys of one
+// task share AttachmentPrefix, so its contents can be removed together.
+func AttachmentKey(tenantID, taskID, id string) string {
+	return AttachmentPrefix(tenantID, taskID) + id
+}
+
+// AttachmentPrefix returns the key prefix of every attachment of a task
+func AttachmentPrefix(tenantID, taskID string) string {
+	return "attachments/" + tenantID + "/" + taskID + "/"
+}
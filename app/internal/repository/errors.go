@@ -0,0 +1,52 @@
+package repository
+
+import "errors"
+
+// Sentinel errors returned by stores; wrap-aware callers use errors.Is
+var (
+	// ErrTaskNotFound means no task exists with the given ID
+	ErrTaskNotFound = errors.New("task not found")
+	// ErrVersionConflict means the task changed since the caller read it
+	ErrVersionConflict = errors.New("task version conflict")
+	// ErrBatchAborted marks ops rolled back because another op in an atomic batch failed
+	ErrBatchAborted = errors.New("not applied: another operation in the atomic batch failed")
+	// ErrUserNotFound means no user exists with the given ID
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUserExists means the username or email is already taken
+	ErrUserExists = errors.New("username or email already in use")
+	// ErrNoTenant means a tenant-scoped operation ran on a context without a tenant
+	ErrNoTenant = errors.New("no tenant in context")
+	// ErrAPIKeyNotFound means no API key exists with the given ID or prefix
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrProjectNotFound means no project exists with the given ID in the tenant
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrProjectExists means the tenant already has a project with that name
+	ErrProjectExists = errors.New("project name already in use")
+	// ErrProjectNotEmpty means a project still has tasks and cannot be deleted
+	ErrProjectNotEmpty = errors.New("project still has tasks")
+	// ErrInvalidParent means a parent_id names an unknown task, would create a
+	// cycle or nests subtasks too deeply
+	ErrInvalidParent = errors.New("invalid parent task")
+	// ErrHasSubtasks means a task with subtasks cannot be deleted under the
+	// reject policy
+	ErrHasSubtasks = errors.New("task has subtasks")
+	// ErrParentInTrash means a task cannot be restored while its parent is in
+	// the trash
+	ErrParentInTrash = errors.New("parent task is in the trash")
+	// ErrInvalidDependency means a blocker is unknown or the task itself
+	ErrInvalidDependency = errors.New("invalid dependency")
+	// ErrDependencyExists means the task is already blocked by the blocker
+	ErrDependencyExists = errors.New("dependency already exists")
+	// ErrDependencyNotFound means the task is not blocked by the blocker
+	ErrDependencyNotFound = errors.New("dependency not found")
+	// ErrDependencyCycle means a dependency would make a task wait on itself
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+	// ErrCommentNotFound means no comment exists with the given ID on the task
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrInvalidCommentParent means a reply names an unknown or deleted comment
+	ErrInvalidCommentParent = errors.New("invalid parent comment")
+	// ErrAttachmentNotFound means no attachment exists with the given ID on the task
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	// ErrBlobNotFound means a blob store holds nothing under the key
+	ErrBlobNotFound = errors.New("blob not found")
+)
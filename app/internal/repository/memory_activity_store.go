@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryActivityStore is a thread-safe in-memory ActivityStore
+type MemoryActivityStore struct {
+	mu   sync.RWMutex
+	logs []model.ActivityLog
+}
+
+// NewMemoryActivityStore creates an empty in-memory activity store
+func NewMemoryActivityStore() *MemoryActivityStore {
+	return &MemoryActivityStore{}
+}
+
+// LogActivity records an activity log entry
+func (s *MemoryActivityStore) LogActivity(ctx context.Context, taskID, action, details string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	log := model.ActivityLog{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		TaskID:    taskID,
+		Action:    action,
+		Details:   details,
+		Timestamp: time.Now(),
+	}
+
+	s.mu.Lock()
+	s.logs = append(s.logs, log)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// LogActivities records several activity log entries. Entries without a
+// tenant are rejected.
+func (s *MemoryActivityStore) LogActivities(_ context.Context, logs []model.ActivityLog) error {
+	for _, log := range logs {
+		if log.TenantID == "" {
+			return ErrNoTenant
+		}
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, log := range logs {
+		log.ID = uuid.New().String()
+		if log.Timestamp.IsZero() {
+			log.Timestamp = now
+		}
+		s.logs = append(s.logs, log)
+	}
+	return nil
+}
+
+// GetActivities retrieves activity logs for a specific task, newest first
+func (s *MemoryActivityStore) GetActivities(ctx context.Context, taskID string, limit int64) ([]model.ActivityLog, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.find(func(l model.ActivityLog) bool { return l.TenantID == tenantID && l.TaskID == taskID }, limit), nil
+}
+
+// GetActivitiesByAction retrieves the activity logs of a task with one of the
+// given actions, newest first
+func (s *MemoryActivityStore) GetActivitiesByAction(ctx context.Context, taskID string, actions []string, limit int64) ([]model.ActivityLog, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.find(func(l model.ActivityLog) bool {
+		return l.TenantID == tenantID && l.TaskID == taskID && containsString(actions, l.Action)
+	}, limit), nil
+}
+
+// GetRecentActivities retrieves the most recent activity logs across all
+// tasks of the tenant
+func (s *MemoryActivityStore) GetRecentActivities(ctx context.Context, limit int64) ([]model.ActivityLog, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.find(func(l model.ActivityLog) bool { return l.TenantID == tenantID }, limit), nil
+}
+
+// Ping always succeeds for the in-memory store
+func (s *MemoryActivityStore) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *MemoryActivityStore) find(match func(model.ActivityLog) bool, limit int64) []model.ActivityLog {
+	s.mu.RLock()
+	var logs []model.ActivityLog
+	for _, l := range s.logs {
+		if match(l) {
+			// Callers get their own changes, not the stored ones
+			l.Changes = append([]model.FieldChange(nil), l.Changes...)
+			logs = append(logs, l)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp.After(logs[j].Timestamp)
+	})
+
+	if int64(len(logs)) > limit {
+		logs = logs[:limit]
+	}
+	return logs
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryAPIKeyStore is a thread-safe in-memory APIKeyStore. List and Revoke
+// only see keys bound to the context's tenant, unless it is tenant.All.
+type MemoryAPIKeyStore struct {
+	mu     sync.RWMutex
+	keys   map[string]model.APIKey
+	hashes map[string]string // key ID → secret hash
+}
+
+// NewMemoryAPIKeyStore creates an empty in-memory API key store
+func NewMemoryAPIKeyStore() *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{
+		keys:   make(map[string]model.APIKey),
+		hashes: make(map[string]string),
+	}
+}
+
+// Create inserts a new API key
+func (s *MemoryAPIKeyStore) Create(_ context.Context, key model.APIKey, hash string) (*model.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys[key.ID] = key
+	s.hashes[key.ID] = hash
+	return &key, nil
+}
+
+// GetByPrefix retrieves an API key and its secret hash by prefix
+func (s *MemoryAPIKeyStore) GetByPrefix(_ context.Context, prefix string) (*model.APIKey, string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for id, k := range s.keys {
+		if k.Prefix == prefix {
+			return &k, s.hashes[id], nil
+		}
+	}
+	return nil, "", ErrAPIKeyNotFound
+}
+
+// List retrieves all API keys, newest first
+func (s *MemoryAPIKeyStore) List(ctx context.Context) ([]model.APIKey, error) {
+	tenantID, err := tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	keys := make([]model.APIKey, 0, len(s.keys))
+	for _, k := range s.keys {
+		if tenantID == "" || k.TenantID == tenantID {
+			keys = append(keys, k)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.After(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+// Revoke marks a key revoked; revoking twice keeps the first timestamp
+func (s *MemoryAPIKeyStore) Revoke(ctx context.Context, id string, at time.Time) (*model.APIKey, error) {
+	tenantID, err := tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.keys[id]
+	if !ok || (tenantID != "" && k.TenantID != tenantID) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if k.RevokedAt == nil {
+		k.RevokedAt = &at
+		s.keys[id] = k
+	}
+	return &k, nil
+}
+
+// TouchLastUsed records a use, skipping the write if the key was used in the last minute
+func (s *MemoryAPIKeyStore) TouchLastUsed(_ context.Context, id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k, ok := s.keys[id]
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	if k.LastUsedAt == nil || k.LastUsedAt.Before(at.Add(-time.Minute)) {
+		k.LastUsedAt = &at
+		s.keys[id] = k
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryAttachmentStore is a thread-safe in-memory AttachmentStore. It reads
+// the tasks of a MemoryTaskStore; attachments of purged tasks are dropped on
+// the next Create.
+type MemoryAttachmentStore struct {
+	mu          sync.RWMutex
+	attachments []model.Attachment
+	tasks       *MemoryTaskStore
+}
+
+// NewMemoryAttachmentStore creates an empty in-memory attachment store over tasks
+func NewMemoryAttachmentStore(tasks *MemoryTaskStore) *MemoryAttachmentStore {
+	return &MemoryAttachmentStore{tasks: tasks}
+}
+
+// Create stores attachment metadata
+func (s *MemoryAttachmentStore) Create(ctx context.Context, a model.Attachment) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks.tasksByID(tenantID, []string{a.TaskID}, false)[a.TaskID]; !ok {
+		return nil, ErrTaskNotFound
+	}
+	s.prune(tenantID)
+
+	a.TenantID = tenantID
+	a.CreatedAt = time.Now()
+	s.attachments = append(s.attachments, a)
+	return &a, nil
+}
+
+// prune drops the attachments of tenantID whose tasks were purged
+func (s *MemoryAttachmentStore) prune(tenantID string) {
+	var ids []string
+	for _, a := range s.attachments {
+		if a.TenantID == tenantID {
+			ids = append(ids, a.TaskID)
+		}
+	}
+	existing := s.tasks.tasksByID(tenantID, ids, true)
+
+	kept := s.attachments[:0]
+	for _, a := range s.attachments {
+		if _, ok := existing[a.TaskID]; a.TenantID != tenantID || ok {
+			kept = append(kept, a)
+		}
+	}
+	s.attachments = kept
+}
+
+// GetByID retrieves an attachment of a task
+func (s *MemoryAttachmentStore) GetByID(ctx context.Context, taskID, id string) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.attachments {
+		if a.TenantID == tenantID && a.TaskID == taskID && a.ID == id {
+			return &a, nil
+		}
+	}
+	return nil, ErrAttachmentNotFound
+}
+
+// List returns the attachments of a task, oldest first
+func (s *MemoryAttachmentStore) List(ctx context.Context, taskID string) ([]model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var attachments []model.Attachment
+	for _, a := range s.attachments {
+		if a.TenantID == tenantID && a.TaskID == taskID {
+			attachments = append(attachments, a)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(attachments, func(i, j int) bool {
+		return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+	})
+	return attachments, nil
+}
+
+// Delete removes attachment metadata and returns it
+func (s *MemoryAttachmentStore) Delete(ctx context.Context, taskID, id string) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, a := range s.attachments {
+		if a.TenantID == tenantID && a.TaskID == taskID && a.ID == id {
+			s.attachments = append(s.attachments[:i], s.attachments[i+1:]...)
+			return &a, nil
+		}
+	}
+	return nil, ErrAttachmentNotFound
+}
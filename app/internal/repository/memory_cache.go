@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/metrics"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+type memoryCacheEntry struct {
+	task      model.Task
+	expiresAt time.Time
+}
+
+type memorySummaryEntry struct {
+	summary   model.ProjectSummary
+	expiresAt time.Time
+}
+
+// MemoryCache is a thread-safe in-memory TaskCache and ProjectSummaryCache
+// with the same TTL as RedisCache
+type MemoryCache struct {
+	mu        sync.RWMutex
+	entries   map[string]memoryCacheEntry
+	summaries map[string]memorySummaryEntry
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries:   make(map[string]memoryCacheEntry),
+		summaries: make(map[string]memorySummaryEntry),
+	}
+}
+
+// GetTask retrieves a cached task
+func (c *MemoryCache) GetTask(ctx context.Context, id string) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[taskCacheKey(tenantID, id)]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		metrics.CacheMissesTotal.WithLabelValues(taskCacheName).Inc()
+		return nil, nil // Cache miss
+	}
+
+	metrics.CacheHitsTotal.WithLabelValues(taskCacheName).Inc()
+	task := entry.task
+	return &task, nil
+}
+
+// SetTask caches a task
+func (c *MemoryCache) SetTask(_ context.Context, task *model.Task) error {
+	c.mu.Lock()
+	c.entries[taskCacheKey(task.TenantID, task.ID)] = memoryCacheEntry{task: *task, expiresAt: time.Now().Add(taskCacheTTL)}
+	c.mu.Unlock()
+	return nil
+}
+
+// InvalidateTask removes a task from cache
+func (c *MemoryCache) InvalidateTask(ctx context.Context, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	delete(c.entries, taskCacheKey(tenantID, id))
+	c.mu.Unlock()
+	return nil
+}
+
+// InvalidateTasks removes several tasks from cache
+func (c *MemoryCache) InvalidateTasks(ctx context.Context, ids []string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for _, id := range ids {
+		delete(c.entries, taskCacheKey(tenantID, id))
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// InvalidateAll clears the cached tasks of the context's tenant, or of every
+// tenant for tenant.All
+func (c *MemoryCache) InvalidateAll(ctx context.Context) error {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if id == tenant.All {
+		c.entries = make(map[string]memoryCacheEntry)
+		return nil
+	}
+	for key := range c.entries {
+		if strings.HasPrefix(key, taskCacheKey(id, "")) {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// GetProjectSummary retrieves a cached project summary
+func (c *MemoryCache) GetProjectSummary(ctx context.Context, projectID string) (*model.ProjectSummary, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	entry, ok := c.summaries[projectSummaryCacheKey(tenantID, projectID)]
+	c.mu.RUnlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		metrics.CacheMissesTotal.WithLabelValues(projectSummaryCacheName).Inc()
+		return nil, nil // Cache miss
+	}
+
+	metrics.CacheHitsTotal.WithLabelValues(projectSummaryCacheName).Inc()
+	summary := entry.summary
+	return &summary, nil
+}
+
+// SetProjectSummary caches a project summary
+func (c *MemoryCache) SetProjectSummary(ctx context.Context, summary *model.ProjectSummary) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.summaries[projectSummaryCacheKey(tenantID, summary.ProjectID)] = memorySummaryEntry{summary: *summary, expiresAt: time.Now().Add(taskCacheTTL)}
+	c.mu.Unlock()
+	return nil
+}
+
+// InvalidateProjectSummaries removes several project summaries from cache
+func (c *MemoryCache) InvalidateProjectSummaries(ctx context.Context, projectIDs []string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for _, id := range projectIDs {
+		delete(c.summaries, projectSummaryCacheKey(tenantID, id))
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Ping always succeeds for the in-memory cache
+func (c *MemoryCache) Ping(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryCommentStore is a thread-safe in-memory CommentStore
+type MemoryCommentStore struct {
+	mu       sync.RWMutex
+	comments map[string]*model.Comment
+}
+
+// NewMemoryCommentStore creates an empty in-memory comment store
+func NewMemoryCommentStore() *MemoryCommentStore {
+	return &MemoryCommentStore{comments: make(map[string]*model.Comment)}
+}
+
+// CreateComment stores a new comment
+func (s *MemoryCommentStore) CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if comment.ParentID != "" {
+		parent := s.find(tenantID, comment.TaskID, comment.ParentID)
+		if parent == nil {
+			return nil, fmt.Errorf("%w: comment %s not found", ErrInvalidCommentParent, comment.ParentID)
+		}
+		if parent.ParentID != "" {
+			parent = s.find(tenantID, comment.TaskID, parent.ParentID)
+		}
+		if parent == nil || parent.Deleted {
+			return nil, fmt.Errorf("%w: comment %s was deleted", ErrInvalidCommentParent, comment.ParentID)
+		}
+		parent.ReplyCount++
+		comment.ParentID = parent.ID
+	}
+
+	now := time.Now().UTC()
+	comment.ID = uuid.New().String()
+	comment.TenantID = tenantID
+	comment.History = nil
+	comment.ReplyCount = 0
+	comment.Deleted = false
+	comment.CreatedAt, comment.UpdatedAt = now, now
+
+	stored := comment
+	s.comments[comment.ID] = &stored
+	return &comment, nil
+}
+
+// GetComment retrieves a comment of a task
+func (s *MemoryCommentStore) GetComment(ctx context.Context, taskID, id string) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	comment := s.find(tenantID, taskID, id)
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+	return copyComment(comment), nil
+}
+
+// ListComments returns the top-level comments of a task, or the replies to
+// q.ParentID, oldest first
+func (s *MemoryCommentStore) ListComments(ctx context.Context, q model.CommentListQuery) (*model.CommentPage, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	var comments []model.Comment
+	for _, c := range s.comments {
+		if c.TenantID != tenantID || c.TaskID != q.TaskID || c.ParentID != q.ParentID {
+			continue
+		}
+		if q.Cursor != nil && !commentBefore(q.Cursor.CreatedAt, q.Cursor.ID, *c) {
+			continue
+		}
+		comments = append(comments, *copyComment(c))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(comments, func(i, j int) bool {
+		return commentBefore(comments[i].CreatedAt, comments[i].ID, comments[j])
+	})
+
+	page := &model.CommentPage{Comments: comments}
+	if len(comments) > q.PerPage {
+		page.Comments, page.HasMore = comments[:q.PerPage], true
+	}
+	return page, nil
+}
+
+// commentBefore reports whether the comment at (createdAt, id) sorts before c
+func commentBefore(createdAt time.Time, id string, c model.Comment) bool {
+	if !createdAt.Equal(c.CreatedAt) {
+		return createdAt.Before(c.CreatedAt)
+	}
+	return id < c.ID
+}
+
+// UpdateComment replaces the body of a comment, keeping the previous one in
+// the history
+func (s *MemoryCommentStore) UpdateComment(ctx context.Context, taskID, id, body string) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comment := s.find(tenantID, taskID, id)
+	if comment == nil || comment.Deleted {
+		return nil, ErrCommentNotFound
+	}
+	comment.History = append(comment.History, model.CommentRevision{Body: comment.Body, WrittenAt: comment.UpdatedAt})
+	comment.Body = body
+	comment.UpdatedAt = time.Now().UTC()
+
+	return copyComment(comment), nil
+}
+
+// DeleteComment removes a comment without replies, or marks a top-level
+// comment with replies deleted
+func (s *MemoryCommentStore) DeleteComment(ctx context.Context, taskID, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comment := s.find(tenantID, taskID, id)
+	if comment == nil || comment.Deleted {
+		return ErrCommentNotFound
+	}
+
+	if comment.ReplyCount > 0 {
+		comment.Deleted = true
+		comment.Body = ""
+		comment.History = nil
+		comment.UpdatedAt = time.Now().UTC()
+		return nil
+	}
+
+	delete(s.comments, id)
+	if parent := s.find(tenantID, taskID, comment.ParentID); parent != nil {
+		parent.ReplyCount--
+		if parent.Deleted && parent.ReplyCount <= 0 {
+			delete(s.comments, parent.ID)
+		}
+	}
+	return nil
+}
+
+// find returns the stored comment, or nil. The caller must hold s.mu.
+func (s *MemoryCommentStore) find(tenantID, taskID, id string) *model.Comment {
+	c, ok := s.comments[id]
+	if !ok || c.TenantID != tenantID || c.TaskID != taskID {
+		return nil
+	}
+	return c
+}
+
+// copyComment returns a copy of c that shares no history with it
+func copyComment(c *model.Comment) *model.Comment {
+	cp := *c
+	cp.History = append([]model.CommentRevision(nil), c.History...)
+	return &cp
+}
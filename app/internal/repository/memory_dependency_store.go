@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryDependencyStore is a thread-safe in-memory DependencyStore. It reads
+// the tasks of a MemoryTaskStore; edges of trashed tasks are skipped, and
+// those of purged tasks dropped on the next Add.
+type MemoryDependencyStore struct {
+	mu    sync.RWMutex
+	edges []tenantDependency
+	tasks *MemoryTaskStore
+}
+
+// tenantDependency is a dependency together with its tenant
+type tenantDependency struct {
+	tenantID string
+	model.TaskDependency
+}
+
+// NewMemoryDependencyStore creates an empty in-memory dependency store over tasks
+func NewMemoryDependencyStore(tasks *MemoryTaskStore) *MemoryDependencyStore {
+	return &MemoryDependencyStore{tasks: tasks}
+}
+
+// Add records that taskID is blocked by blockerID
+func (s *MemoryDependencyStore) Add(ctx context.Context, taskID, blockerID string) (*model.TaskDependency, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	found := s.tasks.tasksByID(tenantID, []string{taskID, blockerID}, false)
+	_, taskExists := found[taskID]
+	_, blockerExists := found[blockerID]
+	if err := dependencyError(taskID, blockerID, taskExists, blockerExists); err != nil {
+		return nil, err
+	}
+
+	s.prune(tenantID)
+
+	// Walk the blockers of blockerID, looking for taskID
+	blockers := make(map[string][]string)
+	for _, d := range s.edges {
+		if d.tenantID != tenantID {
+			continue
+		}
+		if d.TaskID == taskID && d.BlockerID == blockerID {
+			return nil, ErrDependencyExists
+		}
+		blockers[d.TaskID] = append(blockers[d.TaskID], d.BlockerID)
+	}
+	seen := map[string]bool{blockerID: true}
+	queue := []string{blockerID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, b := range blockers[id] {
+			if b == taskID {
+				return nil, ErrDependencyCycle
+			}
+			if !seen[b] {
+				seen[b] = true
+				queue = append(queue, b)
+			}
+		}
+	}
+
+	dep := model.TaskDependency{TaskID: taskID, BlockerID: blockerID, CreatedAt: time.Now()}
+	s.edges = append(s.edges, tenantDependency{tenantID: tenantID, TaskDependency: dep})
+	return &dep, nil
+}
+
+// prune drops the edges of tenantID whose tasks were purged
+func (s *MemoryDependencyStore) prune(tenantID string) {
+	var ids []string
+	for _, d := range s.edges {
+		if d.tenantID == tenantID {
+			ids = append(ids, d.TaskID, d.BlockerID)
+		}
+	}
+	existing := s.tasks.tasksByID(tenantID, ids, true)
+
+	kept := s.edges[:0]
+	for _, d := range s.edges {
+		_, taskExists := existing[d.TaskID]
+		_, blockerExists := existing[d.BlockerID]
+		if d.tenantID != tenantID || (taskExists && blockerExists) {
+			kept = append(kept, d)
+		}
+	}
+	s.edges = kept
+}
+
+// Remove deletes a dependency
+func (s *MemoryDependencyStore) Remove(ctx context.Context, taskID, blockerID string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, d := range s.edges {
+		if d.tenantID == tenantID && d.TaskID == taskID && d.BlockerID == blockerID {
+			s.edges = append(s.edges[:i], s.edges[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDependencyNotFound
+}
+
+// Blockers returns the tasks blocking taskID, oldest first
+func (s *MemoryDependencyStore) Blockers(ctx context.Context, taskID string) ([]model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	s.mu.RLock()
+	for _, d := range s.edges {
+		if d.tenantID == tenantID && d.TaskID == taskID {
+			ids = append(ids, d.BlockerID)
+		}
+	}
+	s.mu.RUnlock()
+
+	var blockers []model.Task
+	for _, t := range s.tasks.tasksByID(tenantID, ids, false) {
+		blockers = append(blockers, t)
+	}
+	sort.Slice(blockers, func(i, j int) bool {
+		return taskBefore(blockers[i], blockers[j], model.SortCreatedAt, model.OrderAsc)
+	})
+	return blockers, nil
+}
+
+// Edges returns the dependencies between tasks that are all in ids
+func (s *MemoryDependencyStore) Edges(ctx context.Context, ids []string) ([]model.TaskDependency, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var deps []model.TaskDependency
+	for _, d := range s.edges {
+		if d.tenantID == tenantID && containsString(ids, d.TaskID) && containsString(ids, d.BlockerID) {
+			deps = append(deps, d.TaskDependency)
+		}
+	}
+	return deps, nil
+}
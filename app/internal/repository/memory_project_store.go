@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryProjectStore is a thread-safe in-memory ProjectStore. It reads the
+// tasks of a MemoryTaskStore to refuse deleting non-empty projects and to
+// build summaries.
+type MemoryProjectStore struct {
+	mu       sync.RWMutex
+	projects map[string]model.Project
+	tasks    *MemoryTaskStore
+}
+
+// NewMemoryProjectStore creates an empty in-memory project store over tasks
+func NewMemoryProjectStore(tasks *MemoryTaskStore) *MemoryProjectStore {
+	return &MemoryProjectStore{
+		projects: make(map[string]model.Project),
+		tasks:    tasks,
+	}
+}
+
+// Create inserts a new project
+func (s *MemoryProjectStore) Create(ctx context.Context, req model.ProjectCreateRequest) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken(tenantID, "", req.Name) {
+		return nil, ErrProjectExists
+	}
+
+	now := time.Now()
+	project := model.Project{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		OwnerID:     req.OwnerID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	s.projects[project.ID] = project
+	return &project, nil
+}
+
+// taken reports whether another project than id in the tenant has the name
+func (s *MemoryProjectStore) taken(tenantID, id, name string) bool {
+	for _, p := range s.projects {
+		if p.TenantID == tenantID && p.ID != id && strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns the project with id if it belongs to tenantID
+func (s *MemoryProjectStore) lookup(tenantID, id string) (model.Project, bool) {
+	project, ok := s.projects[id]
+	return project, ok && project.TenantID == tenantID
+}
+
+// GetByID retrieves a project by ID
+func (s *MemoryProjectStore) GetByID(ctx context.Context, id string) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	project, ok := s.lookup(tenantID, id)
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrProjectNotFound
+	}
+	return &project, nil
+}
+
+// List retrieves one page of projects ordered by name, with the total count
+func (s *MemoryProjectStore) List(ctx context.Context, page, perPage int, includeArchived bool) ([]model.Project, int, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	projects := make([]model.Project, 0, len(s.projects))
+	for _, p := range s.projects {
+		if p.TenantID == tenantID && (includeArchived || !p.Archived) {
+			projects = append(projects, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(projects, func(i, j int) bool {
+		a, b := strings.ToLower(projects[i].Name), strings.ToLower(projects[j].Name)
+		if a != b {
+			return a < b
+		}
+		return projects[i].ID < projects[j].ID
+	})
+
+	total := len(projects)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return projects[start:end], total, nil
+}
+
+// Update modifies the fields set in req
+func (s *MemoryProjectStore) Update(ctx context.Context, id string, req model.ProjectUpdateRequest) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	project, ok := s.lookup(tenantID, id)
+	if !ok {
+		return nil, ErrProjectNotFound
+	}
+
+	if req.Name != nil {
+		project.Name = *req.Name
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+	}
+	if req.OwnerID != nil {
+		project.OwnerID = nil
+		if *req.OwnerID != "" {
+			owner := *req.OwnerID
+			project.OwnerID = &owner
+		}
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+	}
+	if s.taken(tenantID, id, project.Name) {
+		return nil, ErrProjectExists
+	}
+	project.UpdatedAt = time.Now()
+
+	s.projects[id] = project
+	return &project, nil
+}
+
+// Delete removes a project that has no tasks, trashed or not
+func (s *MemoryProjectStore) Delete(ctx context.Context, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Lock the tasks first: Modify callbacks read projects while holding them
+	s.tasks.mu.RLock()
+	defer s.tasks.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(tenantID, id); !ok {
+		return ErrProjectNotFound
+	}
+	if len(s.tasks.projectTasksLocked(tenantID, id)) > 0 {
+		return ErrProjectNotEmpty
+	}
+	delete(s.projects, id)
+	return nil
+}
+
+// Summary counts the project's tasks per status and priority
+func (s *MemoryProjectStore) Summary(ctx context.Context, id string) (*model.ProjectSummary, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	_, ok := s.lookup(tenantID, id)
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrProjectNotFound
+	}
+
+	summary := model.ProjectSummary{ProjectID: id}
+	summary.FillZeroCounts()
+
+	var completed, countable int
+	for _, t := range s.tasks.projectTasks(tenantID, id) {
+		if !isLive(t) {
+			continue
+		}
+		summary.Total++
+		summary.ByStatus[t.Status]++
+		summary.ByPriority[t.Priority]++
+		if t.Status == "completed" {
+			completed++
+		}
+		if t.Status != "cancelled" {
+			countable++
+		}
+	}
+	if countable > 0 {
+		summary.PercentComplete = math.Round(1000*float64(completed)/float64(countable)) / 10
+	}
+
+	return &summary, nil
+}
+
+// ReleaseOwner clears every owner reference to userID in the tenant
+func (s *MemoryProjectStore) ReleaseOwner(ctx context.Context, userID string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, p := range s.projects {
+		if p.TenantID == tenantID && p.OwnerID != nil && *p.OwnerID == userID {
+			p.OwnerID = nil
+			p.UpdatedAt = time.Now()
+			s.projects[id] = p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// snippetWords bounds the length of an in-memory search snippet
+const snippetWords = 35
+
+// searchTerms lowercases a query and splits it into words, ignoring the
+// websearch operators that Postgres understands (quotes, OR, leading -)
+func searchTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isSearchSeparator) {
+		if word == "or" || word == "and" {
+			continue
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+func isSearchSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// matchSearch reports whether every term occurs in title or description and
+// returns a rank (title matches weigh more) and a highlighted snippet
+func matchSearch(terms []string, title, description string) (bool, float64, string) {
+	if len(terms) == 0 {
+		return false, 0, ""
+	}
+
+	words := strings.FieldsFunc(title+" "+description, unicode.IsSpace)
+	titleWords := len(strings.FieldsFunc(title, unicode.IsSpace))
+
+	var rank float64
+	first := -1
+	highlighted := make([]string, len(words))
+	found := make(map[string]bool, len(terms))
+
+	for i, w := range words {
+		highlighted[i] = html.EscapeString(w)
+		normalized := strings.ToLower(strings.TrimFunc(w, isSearchSeparator))
+		for _, term := range terms {
+			if !strings.HasPrefix(normalized, term) {
+				continue
+			}
+			found[term] = true
+			highlighted[i] = "<mark>" + html.EscapeString(w) + "</mark>"
+			if first < 0 {
+				first = i
+			}
+			if i < titleWords {
+				rank += 1.0
+			} else {
+				rank += 0.4
+			}
+			break
+		}
+	}
+
+	if len(found) < len(terms) {
+		return false, 0, ""
+	}
+
+	// Center the snippet window on the first match
+	start := first - snippetWords/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWords
+	if end > len(highlighted) {
+		end = len(highlighted)
+	}
+
+	return true, rank / float64(len(words)), strings.Join(highlighted[start:end], " ")
+}
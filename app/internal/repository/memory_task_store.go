@@ -0,0 +1,821 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryTaskStore is a thread-safe in-memory TaskStore. Tasks of every
+// tenant share one map; lookups ignore tasks of other tenants and, outside
+// the trash, tasks with DeletedAt set. Modify callbacks run under its lock
+// and may read other stores, so stores that read tasks while holding their
+// own lock must take this one first.
+type MemoryTaskStore struct {
+	mu    sync.RWMutex
+	tasks map[string]model.Task
+	// reminded maps kind + "/" + task ID to the due date last reminded for
+	reminded map[string]time.Time
+}
+
+// NewMemoryTaskStore creates an empty in-memory task store
+func NewMemoryTaskStore() *MemoryTaskStore {
+	return &MemoryTaskStore{
+		tasks:    make(map[string]model.Task),
+		reminded: make(map[string]time.Time),
+	}
+}
+
+// Create inserts a new task
+func (s *MemoryTaskStore) Create(ctx context.Context, req model.TaskCreateRequest) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return createMemoryTask(s.tasks, tenantID, req)
+}
+
+func createMemoryTask(tasks map[string]model.Task, tenantID string, req model.TaskCreateRequest) (*model.Task, error) {
+	if err := checkMemoryParent(tasks, tenantID, "", req.ParentID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	task := model.Task{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Title:       req.Title,
+		Description: req.Description,
+		Status:      "pending",
+		Priority:    req.Priority,
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+		AssigneeID:  req.AssigneeID,
+		ReporterID:  req.ReporterID,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		Tags:        req.Tags,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if task.Priority == "" {
+		task.Priority = "medium"
+	}
+
+	tasks[task.ID] = task
+	return &task, nil
+}
+
+// checkMemoryParent verifies that parentID may become the parent of taskID
+// (empty for a new task) by walking up its ancestors
+func checkMemoryParent(tasks map[string]model.Task, tenantID, taskID string, parentID *string) error {
+	if parentID == nil || *parentID == "" {
+		return nil
+	}
+
+	depth, cycle := 0, false
+	for id := parentID; id != nil && depth <= model.MaxTaskDepth; depth++ {
+		t, ok := lookupMemoryTask(tasks, tenantID, *id)
+		if !ok {
+			break
+		}
+		cycle = cycle || t.ID == taskID
+		id = t.ParentID
+	}
+	return parentError(*parentID, depth, cycle)
+}
+
+// memorySubtasks returns the subtasks of id of every depth for which keep
+// reports true, level by level and by creation within a level. The walk does
+// not descend below subtasks that are not kept.
+func memorySubtasks(tasks map[string]model.Task, tenantID, id string, keep func(model.Task) bool) []model.Task {
+	var subtasks []model.Task
+	level := []string{id}
+	for depth := 0; depth < model.MaxTaskDepth && len(level) > 0; depth++ {
+		var children []model.Task
+		for _, t := range tasks {
+			if t.TenantID == tenantID && t.ParentID != nil && containsString(level, *t.ParentID) && keep(t) {
+				children = append(children, t)
+			}
+		}
+		sort.Slice(children, func(i, j int) bool {
+			return taskBefore(children[i], children[j], model.SortCreatedAt, model.OrderAsc)
+		})
+
+		level = level[:0]
+		for _, t := range children {
+			level = append(level, t.ID)
+		}
+		subtasks = append(subtasks, children...)
+	}
+	return subtasks
+}
+
+// GetByID retrieves a task by its ID
+func (s *MemoryTaskStore) GetByID(ctx context.Context, id string) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	task, ok := lookupMemoryTask(s.tasks, tenantID, id)
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return &task, nil
+}
+
+// lookupMemoryTask returns the task with id if it belongs to tenantID and is
+// not in the trash
+func lookupMemoryTask(tasks map[string]model.Task, tenantID, id string) (model.Task, bool) {
+	task, ok := tasks[id]
+	return task, ok && task.TenantID == tenantID && isLive(task)
+}
+
+// lookupMemoryTrash returns the task with id if it belongs to tenantID and is
+// in the trash
+func lookupMemoryTrash(tasks map[string]model.Task, tenantID, id string) (model.Task, bool) {
+	task, ok := tasks[id]
+	return task, ok && task.TenantID == tenantID && !isLive(task)
+}
+
+// isLive reports whether a task is not in the trash
+func isLive(t model.Task) bool {
+	return t.DeletedAt == nil
+}
+
+// List retrieves one page of filtered, sorted tasks
+func (s *MemoryTaskStore) List(ctx context.Context, q model.TaskListQuery) (*model.TaskPage, error) {
+	q.Normalize()
+
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := searchTerms(q.Filter.Search)
+
+	s.mu.RLock()
+	matched := make([]model.Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		if t.TenantID != tenantID || !matchesFilter(t, q.Filter) {
+			continue
+		}
+		if q.Filter.Search != "" {
+			ok, rank, snippet := matchSearch(terms, t.Title, t.Description)
+			if !ok {
+				continue
+			}
+			t.Rank, t.Snippet = rank, snippet
+		}
+		matched = append(matched, t)
+	}
+	s.mu.RUnlock()
+
+	page := &model.TaskPage{}
+	if q.IncludeTotal {
+		total := len(matched)
+		page.Total = &total
+	}
+
+	before := func(a, b model.Task) bool { return taskBefore(a, b, q.Sort, q.Order) }
+	sort.Slice(matched, func(i, j int) bool {
+		return before(matched[i], matched[j])
+	})
+
+	// Apply the keyset position, scanning towards the end or the start
+	backward := q.Cursor != nil && q.Cursor.Backward
+	if q.Cursor != nil {
+		pivot, err := q.Cursor.Pivot()
+		if err != nil {
+			return nil, err
+		}
+		kept := matched[:0]
+		for _, t := range matched {
+			if (!backward && before(pivot, t)) || (backward && before(t, pivot)) {
+				kept = append(kept, t)
+			}
+		}
+		matched = kept
+	}
+	if backward {
+		reverseTasks(matched)
+	}
+
+	start := q.Offset()
+	if start > len(matched) {
+		start = len(matched)
+	}
+	matched = matched[start:]
+
+	if len(matched) > q.PerPage {
+		matched = matched[:q.PerPage]
+		page.HasMore = true
+	}
+	page.Tasks = append([]model.Task(nil), matched...)
+	if backward {
+		reverseTasks(page.Tasks)
+	}
+
+	return page, nil
+}
+
+// matchesFilter reports whether t satisfies every set field of f
+func matchesFilter(t model.Task, f model.TaskFilter) bool {
+	if isLive(t) == f.Trashed {
+		return false
+	}
+	if len(f.IDs) > 0 && !containsString(f.IDs, t.ID) {
+		return false
+	}
+	if len(f.Statuses) > 0 && !containsString(f.Statuses, t.Status) {
+		return false
+	}
+	if len(f.Priorities) > 0 && !containsString(f.Priorities, t.Priority) {
+		return false
+	}
+	if f.CreatedAfter != nil && t.CreatedAt.Before(*f.CreatedAfter) {
+		return false
+	}
+	if f.CreatedBefore != nil && !t.CreatedAt.Before(*f.CreatedBefore) {
+		return false
+	}
+	if f.UpdatedAfter != nil && t.UpdatedAt.Before(*f.UpdatedAfter) {
+		return false
+	}
+	if f.UpdatedBefore != nil && !t.UpdatedAt.Before(*f.UpdatedBefore) {
+		return false
+	}
+	if (f.DueAfter != nil || f.DueBefore != nil) && t.DueAt == nil {
+		return false
+	}
+	if f.DueAfter != nil && t.DueAt.Before(*f.DueAfter) {
+		return false
+	}
+	if f.DueBefore != nil && !t.DueAt.Before(*f.DueBefore) {
+		return false
+	}
+	if len(f.AssigneeIDs) > 0 && (t.AssigneeID == nil || !containsString(f.AssigneeIDs, *t.AssigneeID)) {
+		return false
+	}
+	if len(f.ReporterIDs) > 0 && (t.ReporterID == nil || !containsString(f.ReporterIDs, *t.ReporterID)) {
+		return false
+	}
+	if len(f.ProjectIDs) > 0 && (t.ProjectID == nil || !containsString(f.ProjectIDs, *t.ProjectID)) {
+		return false
+	}
+	if len(f.ParentIDs) > 0 && (t.ParentID == nil || !containsString(f.ParentIDs, *t.ParentID)) {
+		return false
+	}
+	if len(f.TagsAny) > 0 && !containsAny(t.Tags, f.TagsAny) {
+		return false
+	}
+	for _, tag := range f.TagsAll {
+		if !containsString(t.Tags, tag) {
+			return false
+		}
+	}
+	if f.OpenOnly && !t.IsOpen() {
+		return false
+	}
+	return true
+}
+
+// taskBefore reports whether a sorts before b by (sort key, id) in the given order
+func taskBefore(a, b model.Task, sortField, order string) bool {
+	c := 0
+	switch sortField {
+	case model.SortUpdatedAt:
+		c = a.UpdatedAt.Compare(b.UpdatedAt)
+	case model.SortPriority:
+		c = model.PriorityRank(a.Priority) - model.PriorityRank(b.Priority)
+	case model.SortTitle:
+		c = strings.Compare(a.Title, b.Title)
+	case model.SortRelevance:
+		switch {
+		case a.Rank < b.Rank:
+			c = -1
+		case a.Rank > b.Rank:
+			c = 1
+		}
+	default:
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ID, b.ID)
+	}
+
+	if order == model.OrderAsc {
+		return c < 0
+	}
+	return c > 0
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAny reports whether list holds at least one of values
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if containsString(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update modifies an existing task. When expectedVersion is non-zero the
+// update only applies if the stored version still matches.
+func (s *MemoryTaskStore) Update(ctx context.Context, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return updateMemoryTask(s.tasks, tenantID, id, req, expectedVersion)
+}
+
+func updateMemoryTask(tasks map[string]model.Task, tenantID, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error) {
+	task, ok := lookupMemoryTask(tasks, tenantID, id)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+	if err := checkMemoryParent(tasks, tenantID, id, req.ParentID); err != nil {
+		return nil, err
+	}
+
+	req.ApplyTo(&task)
+	task.Version++
+	task.UpdatedAt = time.Now()
+
+	tasks[id] = task
+	return &task, nil
+}
+
+// Modify applies fn to a copy of the task and stores it if fn succeeds
+func (s *MemoryTaskStore) Modify(ctx context.Context, id string, expectedVersion int, fn func(*model.Task) error) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return modifyMemoryTask(s.tasks, tenantID, id, expectedVersion, fn)
+}
+
+func modifyMemoryTask(tasks map[string]model.Task, tenantID, id string, expectedVersion int, fn func(*model.Task) error) (*model.Task, error) {
+	task, ok := lookupMemoryTask(tasks, tenantID, id)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+
+	parentID := task.ParentID
+	if err := fn(&task); err != nil {
+		return nil, err
+	}
+	if task.ParentID != nil && (parentID == nil || *parentID != *task.ParentID) {
+		if err := checkMemoryParent(tasks, tenantID, id, task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	task.Version++
+	task.UpdatedAt = time.Now()
+
+	tasks[id] = task
+	return &task, nil
+}
+
+// Delete moves a task to the trash, subject to the same version check as
+// Update, and handles its subtasks according to onSubtasks
+func (s *MemoryTaskStore) Delete(ctx context.Context, id string, expectedVersion int, onSubtasks string) ([]model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, subtasks, err := trashMemoryTask(s.tasks, tenantID, id, expectedVersion, onSubtasks)
+	return subtasks, err
+}
+
+// trashMemoryTask moves a task to the trash and returns it with the subtasks
+// it trashed or detached. Subtasks trashed with the task share its DeletedAt.
+func trashMemoryTask(tasks map[string]model.Task, tenantID, id string, expectedVersion int, onSubtasks string) (*model.Task, []model.Task, error) {
+	task, ok := lookupMemoryTask(tasks, tenantID, id)
+	if !ok {
+		return nil, nil, ErrTaskNotFound
+	}
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return nil, nil, ErrVersionConflict
+	}
+
+	now := time.Now()
+	subtasks := memorySubtasks(tasks, tenantID, id, isLive)
+	switch onSubtasks {
+	case model.SubtasksCascade:
+		for i := range subtasks {
+			subtasks[i].DeletedAt = &now
+			subtasks[i].Version++
+			tasks[subtasks[i].ID] = subtasks[i]
+		}
+	case model.SubtasksOrphan:
+		var detached []model.Task
+		for _, t := range subtasks {
+			if *t.ParentID != id {
+				continue
+			}
+			t.ParentID = nil
+			t.Version++
+			t.UpdatedAt = now
+			tasks[t.ID] = t
+			detached = append(detached, t)
+		}
+		subtasks = detached
+	default:
+		if len(subtasks) > 0 {
+			return nil, nil, ErrHasSubtasks
+		}
+	}
+
+	task.DeletedAt = &now
+	task.Version++
+	tasks[id] = task
+	return &task, subtasks, nil
+}
+
+// Restore takes a task out of the trash together with the subtasks that were
+// trashed with it
+func (s *MemoryTaskStore) Restore(ctx context.Context, id string) (*model.Task, []model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := lookupMemoryTrash(s.tasks, tenantID, id)
+	if !ok {
+		return nil, nil, ErrTaskNotFound
+	}
+	if task.ParentID != nil {
+		if _, trashed := lookupMemoryTrash(s.tasks, tenantID, *task.ParentID); trashed {
+			return nil, nil, ErrParentInTrash
+		}
+	}
+
+	deletedAt := *task.DeletedAt
+	subtasks := memorySubtasks(s.tasks, tenantID, id, func(t model.Task) bool {
+		return !isLive(t) && t.DeletedAt.Equal(deletedAt)
+	})
+	for i := range subtasks {
+		subtasks[i].DeletedAt = nil
+		subtasks[i].Version++
+		s.tasks[subtasks[i].ID] = subtasks[i]
+	}
+
+	task.DeletedAt = nil
+	task.Version++
+	s.tasks[id] = task
+	return &task, subtasks, nil
+}
+
+// Purge deletes a trashed task and its trashed subtasks
+func (s *MemoryTaskStore) Purge(ctx context.Context, id string) (*model.Task, []model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := lookupMemoryTrash(s.tasks, tenantID, id)
+	if !ok {
+		return nil, nil, ErrTaskNotFound
+	}
+	subtasks := memorySubtasks(s.tasks, tenantID, id, func(t model.Task) bool { return !isLive(t) })
+
+	s.purge(append([]model.Task{task}, subtasks...))
+	return &task, subtasks, nil
+}
+
+// PurgeTrash deletes up to limit tasks of every tenant trashed before cutoff,
+// oldest first
+func (s *MemoryTaskStore) PurgeTrash(_ context.Context, cutoff time.Time, limit int) ([]model.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []model.Task
+	for _, t := range s.tasks {
+		if !isLive(t) && t.DeletedAt.Before(cutoff) {
+			expired = append(expired, t)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].DeletedAt.Before(*expired[j].DeletedAt) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	s.purge(expired)
+	return expired, nil
+}
+
+// purge deletes tasks and, like the parent_id foreign key, clears the parent
+// of any task left pointing at one of them; s.mu must be held
+func (s *MemoryTaskStore) purge(tasks []model.Task) {
+	removed := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		delete(s.tasks, t.ID)
+		delete(s.reminded, model.ReminderDueSoon+"/"+t.ID)
+		delete(s.reminded, model.ReminderOverdue+"/"+t.ID)
+		removed[t.ID] = true
+	}
+
+	for id, t := range s.tasks {
+		if t.ParentID != nil && removed[*t.ParentID] {
+			t.ParentID = nil
+			s.tasks[id] = t
+		}
+	}
+}
+
+// Batch applies ops in order. In atomic mode the ops run against a copy of
+// the store that replaces it only if every op succeeds.
+func (s *MemoryTaskStore) Batch(ctx context.Context, ops []TaskBatchOp, atomic bool) ([]TaskBatchResult, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := s.tasks
+	if atomic {
+		tasks = make(map[string]model.Task, len(s.tasks))
+		for id, t := range s.tasks {
+			tasks[id] = t
+		}
+	}
+
+	results := make([]TaskBatchResult, len(ops))
+	for i, op := range ops {
+		var task *model.Task
+		var subtasks []model.Task
+		var err error
+
+		switch {
+		case op.Kind == model.BatchOpCreate:
+			task, err = createMemoryTask(tasks, tenantID, op.Create)
+		case op.Kind == model.BatchOpUpdate && op.Modify != nil:
+			task, err = modifyMemoryTask(tasks, tenantID, op.ID, op.ExpectedVersion, op.Modify)
+		case op.Kind == model.BatchOpUpdate:
+			task, err = updateMemoryTask(tasks, tenantID, op.ID, op.Update, op.ExpectedVersion)
+		case op.Kind == model.BatchOpDelete:
+			task, subtasks, err = trashMemoryTask(tasks, tenantID, op.ID, op.ExpectedVersion, op.OnSubtasks)
+		default:
+			err = fmt.Errorf("unknown batch operation %q", op.Kind)
+		}
+
+		if err != nil && atomic {
+			abortBatch(results, i, err)
+			return results, nil
+		}
+		results[i] = TaskBatchResult{Task: task, Subtasks: subtasks, Err: err}
+	}
+
+	s.tasks = tasks
+	return results, nil
+}
+
+// ClaimReminders hands due tasks of every tenant to fn and marks them reminded
+// if it succeeds
+func (s *MemoryTaskStore) ClaimReminders(_ context.Context, kind string, now time.Time, window time.Duration, limit int, fn func([]model.Task) error) error {
+	if kind != model.ReminderDueSoon && kind != model.ReminderOverdue {
+		return fmt.Errorf("unknown reminder kind %q", kind)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if !t.IsOpen() || t.DueAt == nil || !isLive(t) {
+			continue
+		}
+		if last, ok := s.reminded[kind+"/"+t.ID]; ok && last.Equal(*t.DueAt) {
+			continue
+		}
+
+		due := t.IsOverdue(now)
+		if kind == model.ReminderDueSoon {
+			due = !t.DueAt.Before(now) && t.DueAt.Before(now.Add(window))
+		}
+		if due {
+			tasks = append(tasks, t)
+		}
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].DueAt.Before(*tasks[j].DueAt) })
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	if err := fn(tasks); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		s.reminded[kind+"/"+t.ID] = *t.DueAt
+	}
+	return nil
+}
+
+// ReleaseUser clears every assignee and reporter reference to userID in the
+// tenant
+func (s *MemoryTaskStore) ReleaseUser(ctx context.Context, userID string) ([]ReleasedTask, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var released []ReleasedTask
+	for id, t := range s.tasks {
+		if t.TenantID != tenantID {
+			continue
+		}
+		wasAssignee := t.AssigneeID != nil && *t.AssigneeID == userID
+		wasReporter := t.ReporterID != nil && *t.ReporterID == userID
+		if !wasAssignee && !wasReporter {
+			continue
+		}
+
+		if wasAssignee {
+			t.AssigneeID = nil
+		}
+		if wasReporter {
+			t.ReporterID = nil
+		}
+		t.Version++
+		t.UpdatedAt = time.Now()
+
+		s.tasks[id] = t
+		released = append(released, ReleasedTask{Task: t, WasAssignee: wasAssignee})
+	}
+
+	return released, nil
+}
+
+// TagCounts counts the tasks carrying each tag of the tenant, most used first
+func (s *MemoryTaskStore) TagCounts(ctx context.Context) ([]model.TagCount, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	s.mu.RLock()
+	for _, t := range s.tasks {
+		if t.TenantID != tenantID || !isLive(t) {
+			continue
+		}
+		for _, tag := range t.Tags {
+			counts[tag]++
+		}
+	}
+	s.mu.RUnlock()
+
+	result := make([]model.TagCount, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, model.TagCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
+
+// Subtree returns a task followed by its subtasks, level by level
+func (s *MemoryTaskStore) Subtree(ctx context.Context, id string) ([]model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	task, ok := lookupMemoryTask(s.tasks, tenantID, id)
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+	return append([]model.Task{task}, memorySubtasks(s.tasks, tenantID, id, isLive)...), nil
+}
+
+// SubtaskProgress counts the subtasks below each of ids, at every depth
+func (s *MemoryTaskStore) SubtaskProgress(ctx context.Context, ids []string) (map[string]model.TaskProgress, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	progress := make(map[string]model.TaskProgress)
+	for _, id := range ids {
+		var p model.TaskProgress
+		for _, t := range memorySubtasks(s.tasks, tenantID, id, isLive) {
+			p.Add(t.Status)
+		}
+		if p.Subtasks > 0 {
+			progress[id] = p
+		}
+	}
+	return progress, nil
+}
+
+// projectTasks returns the tasks of tenantID that belong to projectID,
+// including those in the trash
+func (s *MemoryTaskStore) projectTasks(tenantID, projectID string) []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.projectTasksLocked(tenantID, projectID)
+}
+
+// projectTasksLocked is projectTasks for callers holding s.mu
+func (s *MemoryTaskStore) projectTasksLocked(tenantID, projectID string) []model.Task {
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if t.TenantID == tenantID && t.ProjectID != nil && *t.ProjectID == projectID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// tasksByID returns the tasks of tenantID among ids, keyed by ID. Tasks in
+// the trash are included only if withTrash is set.
+func (s *MemoryTaskStore) tasksByID(tenantID string, ids []string, withTrash bool) map[string]model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make(map[string]model.Task, len(ids))
+	for _, id := range ids {
+		if t, ok := s.tasks[id]; ok && t.TenantID == tenantID && (withTrash || isLive(t)) {
+			tasks[id] = t
+		}
+	}
+	return tasks
+}
+
+// Ping always succeeds for the in-memory store
+func (s *MemoryTaskStore) Ping(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// MemoryUserStore is a thread-safe in-memory UserStore
+type MemoryUserStore struct {
+	mu    sync.RWMutex
+	users map[string]model.User
+}
+
+// NewMemoryUserStore creates an empty in-memory user store
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{users: make(map[string]model.User)}
+}
+
+// Create inserts a new user into the context's tenant
+func (s *MemoryUserStore) Create(ctx context.Context, req model.UserCreateRequest) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.taken(tenantID, "", req.Username, req.Email) {
+		return nil, ErrUserExists
+	}
+
+	now := time.Now()
+	user := model.User{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Username:    req.Username,
+		Email:       req.Email,
+		DisplayName: req.DisplayName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	s.users[user.ID] = user
+	return &user, nil
+}
+
+// taken reports whether another user of the tenant than id has the username
+// or email
+func (s *MemoryUserStore) taken(tenantID, id, username, email string) bool {
+	for _, u := range s.users {
+		if u.ID == id || u.TenantID != tenantID {
+			continue
+		}
+		if strings.EqualFold(u.Username, username) || strings.EqualFold(u.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetByID retrieves a user of the context's tenant by ID
+func (s *MemoryUserStore) GetByID(ctx context.Context, id string) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	user, ok := s.users[id]
+	s.mu.RUnlock()
+
+	if !ok || user.TenantID != tenantID {
+		return nil, ErrUserNotFound
+	}
+	return &user, nil
+}
+
+// List retrieves one page of the tenant's users ordered by username, with
+// the total count
+func (s *MemoryUserStore) List(ctx context.Context, page, perPage int) ([]model.User, int, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	users := make([]model.User, 0, len(s.users))
+	for _, u := range s.users {
+		if u.TenantID == tenantID {
+			users = append(users, u)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].Username) < strings.ToLower(users[j].Username)
+	})
+
+	total := len(users)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return users[start:end], total, nil
+}
+
+// Update modifies the fields set in req
+func (s *MemoryUserStore) Update(ctx context.Context, id string, req model.UserUpdateRequest) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok || user.TenantID != tenantID {
+		return nil, ErrUserNotFound
+	}
+
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+	if req.Email != nil {
+		user.Email = *req.Email
+	}
+	if req.DisplayName != nil {
+		user.DisplayName = *req.DisplayName
+	}
+	if s.taken(tenantID, id, user.Username, user.Email) {
+		return nil, ErrUserExists
+	}
+	user.UpdatedAt = time.Now()
+
+	s.users[id] = user
+	return &user, nil
+}
+
+// Delete removes a user of the context's tenant
+func (s *MemoryUserStore) Delete(ctx context.Context, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[id]; !ok || user.TenantID != tenantID {
+		return ErrUserNotFound
+	}
+	delete(s.users, id)
+	return nil
+}
@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// notDeleted excludes comments marked deleted
+var notDeleted = bson.E{Key: "deleted", Value: bson.D{{Key: "$ne", Value: true}}}
+
+// commentFilter matches one comment of a task in the tenant
+func commentFilter(tenantID, taskID, id string) bson.D {
+	return bson.D{{Key: "_id", Value: id}, {Key: "tenant_id", Value: tenantID}, {Key: "task_id", Value: taskID}}
+}
+
+// CreateComment stores a new comment. Timestamps are kept at millisecond
+// precision, as MongoDB stores them.
+func (r *MongoRepository) CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	comment.ID = uuid.New().String()
+	comment.TenantID = tenantID
+	comment.History = nil
+	comment.ReplyCount = 0
+	comment.Deleted = false
+	comment.CreatedAt, comment.UpdatedAt = now, now
+
+	if comment.ParentID != "" {
+		if comment.ParentID, err = r.addReply(ctx, tenantID, comment.TaskID, comment.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := r.comments.InsertOne(ctx, comment); err != nil {
+		if comment.ParentID != "" {
+			_, _ = r.comments.UpdateOne(ctx, commentFilter(tenantID, comment.TaskID, comment.ParentID),
+				bson.D{{Key: "$inc", Value: bson.D{{Key: "reply_count", Value: -1}}}})
+		}
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// addReply counts a new reply on the top-level comment of parentID and
+// returns that comment's ID. Counting before the insert keeps a concurrent
+// delete from removing the parent.
+func (r *MongoRepository) addReply(ctx context.Context, tenantID, taskID, parentID string) (string, error) {
+	var parent model.Comment
+	err := r.comments.FindOne(ctx, commentFilter(tenantID, taskID, parentID)).Decode(&parent)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", fmt.Errorf("%w: comment %s not found", ErrInvalidCommentParent, parentID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get parent comment: %w", err)
+	}
+	if parent.ParentID != "" {
+		parentID = parent.ParentID
+	}
+
+	filter := append(commentFilter(tenantID, taskID, parentID), notDeleted)
+	result, err := r.comments.UpdateOne(ctx, filter, bson.D{{Key: "$inc", Value: bson.D{{Key: "reply_count", Value: 1}}}})
+	if err != nil {
+		return "", fmt.Errorf("failed to count reply: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return "", fmt.Errorf("%w: comment %s was deleted", ErrInvalidCommentParent, parentID)
+	}
+
+	return parentID, nil
+}
+
+// GetComment retrieves a comment of a task
+func (r *MongoRepository) GetComment(ctx context.Context, taskID, id string) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment model.Comment
+	err = r.comments.FindOne(ctx, commentFilter(tenantID, taskID, id)).Decode(&comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// ListComments returns the top-level comments of a task, or the replies to
+// q.ParentID, oldest first
+func (r *MongoRepository) ListComments(ctx context.Context, q model.CommentListQuery) (*model.CommentPage, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A nil parent_id also matches top-level comments, which have none
+	filter := bson.D{{Key: "tenant_id", Value: tenantID}, {Key: "task_id", Value: q.TaskID}}
+	if q.ParentID == "" {
+		filter = append(filter, bson.E{Key: "parent_id", Value: nil})
+	} else {
+		filter = append(filter, bson.E{Key: "parent_id", Value: q.ParentID})
+	}
+	if q.Cursor != nil {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.D{{Key: "created_at", Value: bson.D{{Key: "$gt", Value: q.Cursor.CreatedAt}}}},
+			bson.D{{Key: "created_at", Value: q.Cursor.CreatedAt}, {Key: "_id", Value: bson.D{{Key: "$gt", Value: q.Cursor.ID}}}},
+		}})
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(q.PerPage) + 1)
+
+	cursor, err := r.comments.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var comments []model.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+
+	page := &model.CommentPage{Comments: comments}
+	if len(comments) > q.PerPage {
+		page.Comments, page.HasMore = comments[:q.PerPage], true
+	}
+	return page, nil
+}
+
+// UpdateComment replaces the body of a comment. The previous body moves
+// into the history within the same update, so concurrent edits lose no
+// revision.
+func (r *MongoRepository) UpdateComment(ctx context.Context, taskID, id, body string) (*model.Comment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "history", Value: bson.D{{Key: "$concatArrays", Value: bson.A{
+			bson.D{{Key: "$ifNull", Value: bson.A{"$history", bson.A{}}}},
+			bson.A{bson.D{{Key: "body", Value: "$body"}, {Key: "written_at", Value: "$updated_at"}}},
+		}}}},
+		// $literal keeps a body starting with "$" from reading as a field path
+		{Key: "body", Value: bson.D{{Key: "$literal", Value: body}}},
+		{Key: "updated_at", Value: time.Now().UTC()},
+	}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var comment model.Comment
+	filter := append(commentFilter(tenantID, taskID, id), notDeleted)
+	err = r.comments.FindOneAndUpdate(ctx, filter, update, opts).Decode(&comment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// DeleteComment removes a comment without replies. A top-level comment
+// with replies loses its body and history and stays as a marker until its
+// last reply is deleted.
+func (r *MongoRepository) DeleteComment(ctx context.Context, taskID, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	comment, err := r.GetComment(ctx, taskID, id)
+	if err != nil {
+		return err
+	}
+	if comment.Deleted {
+		return ErrCommentNotFound
+	}
+
+	// The reply_count condition keeps a reply counted meanwhile from losing its parent
+	filter := append(commentFilter(tenantID, taskID, id), bson.E{Key: "reply_count", Value: bson.D{{Key: "$lte", Value: 0}}})
+	result, err := r.comments.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		update := bson.D{
+			{Key: "$set", Value: bson.D{{Key: "deleted", Value: true}, {Key: "body", Value: ""}, {Key: "updated_at", Value: time.Now().UTC()}}},
+			{Key: "$unset", Value: bson.D{{Key: "history", Value: ""}}},
+		}
+		marked, err := r.comments.UpdateOne(ctx, append(commentFilter(tenantID, taskID, id), notDeleted), update)
+		if err != nil {
+			return fmt.Errorf("failed to delete comment: %w", err)
+		}
+		if marked.MatchedCount == 0 {
+			return ErrCommentNotFound
+		}
+		// The last reply may have gone while the comment was being marked
+		return r.removeEmptyDeleted(ctx, tenantID, taskID, id)
+	}
+
+	if comment.ParentID != "" {
+		update := bson.D{{Key: "$inc", Value: bson.D{{Key: "reply_count", Value: -1}}}}
+		if _, err := r.comments.UpdateOne(ctx, commentFilter(tenantID, taskID, comment.ParentID), update); err != nil {
+			return fmt.Errorf("failed to uncount reply: %w", err)
+		}
+		return r.removeEmptyDeleted(ctx, tenantID, taskID, comment.ParentID)
+	}
+
+	return nil
+}
+
+// removeEmptyDeleted removes a comment marked deleted once it has no replies left
+func (r *MongoRepository) removeEmptyDeleted(ctx context.Context, tenantID, taskID, id string) error {
+	filter := append(commentFilter(tenantID, taskID, id),
+		bson.E{Key: "deleted", Value: true},
+		bson.E{Key: "reply_count", Value: bson.D{{Key: "$lte", Value: 0}}})
+	if _, err := r.comments.DeleteOne(ctx, filter); err != nil {
+		return fmt.Errorf("failed to remove deleted comment: %w", err)
+	}
+	return nil
+}
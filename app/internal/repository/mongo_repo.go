@@ -12,28 +12,52 @@ import (
 	"github.com/hamfa/task-manager/internal/model"
 )
 
-// MongoRepository handles MongoDB operations for activity logging
+// MongoRepository handles MongoDB operations for activity logging and task
+// comments. Every document carries a tenant_id and queries only see the
+// context's tenant.
 type MongoRepository struct {
 	collection *mongo.Collection
+	comments   *mongo.Collection
 }
 
 // NewMongoRepository creates a new MongoDB repository
 func NewMongoRepository(db *mongo.Database) *MongoRepository {
 	return &MongoRepository{
 		collection: db.Collection("activity_logs"),
+		comments:   db.Collection("comments"),
 	}
 }
 
+// BackfillTenant assigns activity logs written before tenants existed to
+// tenantID, as migration 010 does for their tasks. It is a no-op once every
+// entry has a tenant.
+func (r *MongoRepository) BackfillTenant(ctx context.Context, tenantID string) (int64, error) {
+	filter := bson.D{{Key: "tenant_id", Value: bson.D{{Key: "$exists", Value: false}}}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "tenant_id", Value: tenantID}}}}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill activity tenants: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
 // LogActivity records an activity log entry
 func (r *MongoRepository) LogActivity(ctx context.Context, taskID, action, details string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
 	log := model.ActivityLog{
+		TenantID:  tenantID,
 		TaskID:    taskID,
 		Action:    action,
 		Details:   details,
 		Timestamp: time.Now(),
 	}
 
-	_, err := r.collection.InsertOne(ctx, log)
+	_, err = r.collection.InsertOne(ctx, log)
 	if err != nil {
 		return fmt.Errorf("failed to log activity: %w", err)
 	}
@@ -41,6 +65,33 @@ func (r *MongoRepository) LogActivity(ctx context.Context, taskID, action, detai
 	return nil
 }
 
+// LogActivities records several activity log entries with one InsertMany.
+// Entries without a timestamp are stamped with the current time; entries
+// without a tenant are rejected.
+func (r *MongoRepository) LogActivities(ctx context.Context, logs []model.ActivityLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(logs))
+	for i, log := range logs {
+		if log.TenantID == "" {
+			return ErrNoTenant
+		}
+		if log.Timestamp.IsZero() {
+			log.Timestamp = now
+		}
+		docs[i] = log
+	}
+
+	if _, err := r.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to log activities: %w", err)
+	}
+
+	return nil
+}
+
 // GetActivities retrieves activity logs for a specific task
 func (r *MongoRepository) GetActivities(ctx context.Context, taskID string, limit int64) ([]model.ActivityLog, error) {
 	if limit <= 0 {
@@ -51,7 +102,45 @@ func (r *MongoRepository) GetActivities(ctx context.Context, taskID string, limi
 		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
 		SetLimit(limit)
 
-	filter := bson.D{{Key: "task_id", Value: taskID}}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.D{{Key: "tenant_id", Value: tenantID}, {Key: "task_id", Value: taskID}}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activities: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var logs []model.ActivityLog
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, fmt.Errorf("failed to decode activities: %w", err)
+	}
+
+	return logs, nil
+}
+
+// GetActivitiesByAction retrieves the activity logs of a task with one of the
+// given actions, newest first
+func (r *MongoRepository) GetActivitiesByAction(ctx context.Context, taskID string, actions []string, limit int64) ([]model.ActivityLog, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
+		SetLimit(limit)
+	filter := bson.D{
+		{Key: "tenant_id", Value: tenantID},
+		{Key: "task_id", Value: taskID},
+		{Key: "action", Value: bson.D{{Key: "$in", Value: actions}}},
+	}
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -67,17 +156,22 @@ func (r *MongoRepository) GetActivities(ctx context.Context, taskID string, limi
 	return logs, nil
 }
 
-// GetRecentActivities retrieves the most recent activity logs across all tasks
+// GetRecentActivities retrieves the most recent activity logs across all
+// tasks of the tenant
 func (r *MongoRepository) GetRecentActivities(ctx context.Context, limit int64) ([]model.ActivityLog, error) {
 	if limit <= 0 {
 		limit = 20
 	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	opts := options.Find().
 		SetSort(bson.D{{Key: "timestamp", Value: -1}}).
 		SetLimit(limit)
 
-	cursor, err := r.collection.Find(ctx, bson.D{}, opts)
+	cursor, err := r.collection.Find(ctx, bson.D{{Key: "tenant_id", Value: tenantID}}, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent activities: %w", err)
 	}
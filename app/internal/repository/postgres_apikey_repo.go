@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// PostgresAPIKeyRepository handles PostgreSQL operations for API keys. List
+// and Revoke only see keys bound to the context's tenant, unless it is
+// tenant.All.
+type PostgresAPIKeyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAPIKeyRepository creates a new PostgreSQL API key repository
+func NewPostgresAPIKeyRepository(pool *pgxpool.Pool) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{pool: pool}
+}
+
+// apiKeyColumns is the column list matching scanAPIKey
+const apiKeyColumns = `id, name, prefix, scopes, COALESCE(tenant_id, ''), created_by, created_at, expires_at, last_used_at, revoked_at`
+
+func scanAPIKey(row pgx.Row, extra ...interface{}) (*model.APIKey, error) {
+	var k model.APIKey
+	dest := []interface{}{&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.TenantID, &k.CreatedBy, &k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// Create inserts a new API key
+func (r *PostgresAPIKeyRepository) Create(ctx context.Context, key model.APIKey, hash string) (*model.APIKey, error) {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, key_hash, scopes, tenant_id, created_by, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9)
+		RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(r.pool.QueryRow(ctx, query,
+		key.ID, key.Name, key.Prefix, hash, key.Scopes, key.TenantID, key.CreatedBy, key.CreatedAt, key.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetByPrefix retrieves an API key and its secret hash by prefix
+func (r *PostgresAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, string, error) {
+	var hash string
+	key, err := scanAPIKey(r.pool.QueryRow(ctx,
+		`SELECT `+apiKeyColumns+`, key_hash FROM api_keys WHERE prefix = $1`, prefix), &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return key, hash, nil
+}
+
+// List retrieves all API keys, newest first
+func (r *PostgresAPIKeyRepository) List(ctx context.Context) ([]model.APIKey, error) {
+	tenantID, err := tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE $1 = '' OR tenant_id = $1
+		ORDER BY created_at DESC`
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api key: %w", err)
+		}
+		keys = append(keys, *k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke marks a key revoked; revoking twice keeps the first timestamp
+func (r *PostgresAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) (*model.APIKey, error) {
+	tenantID, err := tenantFilter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, $2)
+		WHERE id = $1 AND ($3 = '' OR tenant_id = $3)
+		RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(r.pool.QueryRow(ctx, query, id, at, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return key, nil
+}
+
+// TouchLastUsed records a use, skipping the write if the key was used in the last minute
+func (r *PostgresAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	query := `
+		UPDATE api_keys SET last_used_at = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < $2 - INTERVAL '1 minute')`
+
+	if _, err := r.pool.Exec(ctx, query, id, at); err != nil {
+		return fmt.Errorf("failed to touch api key: %w", err)
+	}
+	return nil
+}
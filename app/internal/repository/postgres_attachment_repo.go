@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// PostgresAttachmentRepository handles PostgreSQL operations for attachment
+// metadata. Deleting a task deletes its rows through the foreign key.
+type PostgresAttachmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAttachmentRepository creates a new PostgreSQL attachment repository
+func NewPostgresAttachmentRepository(pool *pgxpool.Pool) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{pool: pool}
+}
+
+// attachmentColumns is the column list matching scanAttachment
+const attachmentColumns = `id, tenant_id, task_id, filename, content_type, size, checksum, storage_key, uploaded_by, created_at`
+
+func scanAttachment(row pgx.Row) (*model.Attachment, error) {
+	var a model.Attachment
+	err := row.Scan(&a.ID, &a.TenantID, &a.TaskID, &a.Filename, &a.ContentType, &a.Size,
+		&a.Checksum, &a.StorageKey, &a.UploadedBy, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Create inserts attachment metadata. The task is checked in the same
+// statement, so a task trashed or purged meanwhile fails the insert.
+func (r *PostgresAttachmentRepository) Create(ctx context.Context, a model.Attachment) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO task_attachments
+			(id, tenant_id, task_id, filename, content_type, size, checksum, storage_key, uploaded_by)
+		SELECT $1, $2, id, $4, $5, $6, $7, $8, $9 FROM tasks
+		WHERE id = $3 AND tenant_id = $2 AND deleted_at IS NULL
+		RETURNING ` + attachmentColumns
+
+	attachment, err := scanAttachment(r.pool.QueryRow(ctx, query,
+		a.ID, tenantID, a.TaskID, a.Filename, a.ContentType, a.Size, a.Checksum, a.StorageKey, a.UploadedBy,
+	))
+	if errors.Is(err, pgx.ErrNoRows) || isForeignKeyViolation(err) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// GetByID retrieves an attachment of a task
+func (r *PostgresAttachmentRepository) GetByID(ctx context.Context, taskID, id string) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments
+		WHERE id = $1 AND task_id = $2 AND tenant_id = $3`
+	attachment, err := scanAttachment(r.pool.QueryRow(ctx, query, id, taskID, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// List returns the attachments of a task, oldest first
+func (r *PostgresAttachmentRepository) List(ctx context.Context, taskID string) ([]model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + attachmentColumns + ` FROM task_attachments
+		WHERE task_id = $1 AND tenant_id = $2
+		ORDER BY created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, taskID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []model.Attachment
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, *a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Delete removes attachment metadata and returns it
+func (r *PostgresAttachmentRepository) Delete(ctx context.Context, taskID, id string) (*model.Attachment, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `DELETE FROM task_attachments WHERE id = $1 AND task_id = $2 AND tenant_id = $3
+		RETURNING ` + attachmentColumns
+	attachment, err := scanAttachment(r.pool.QueryRow(ctx, query, id, taskID, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return attachment, nil
+}
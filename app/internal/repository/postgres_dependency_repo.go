@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// PostgresDependencyRepository handles PostgreSQL operations for task
+// dependencies. Deleting a task deletes its edges through the foreign keys.
+type PostgresDependencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresDependencyRepository creates a new PostgreSQL dependency repository
+func NewPostgresDependencyRepository(pool *pgxpool.Pool) *PostgresDependencyRepository {
+	return &PostgresDependencyRepository{pool: pool}
+}
+
+// Add inserts a dependency after checking both tasks and walking the
+// blocker's own blockers for taskID. Dependency writes of a tenant are
+// serialized with an advisory lock, so two concurrent edges cannot close a
+// cycle together.
+func (r *PostgresDependencyRepository) Add(ctx context.Context, taskID, blockerID string) (*model.TaskDependency, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dep := &model.TaskDependency{TaskID: taskID, BlockerID: blockerID}
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		lock := `SELECT pg_advisory_xact_lock(hashtext('task_dependencies'), hashtext($1))`
+		if _, err := tx.Exec(ctx, lock, tenantID); err != nil {
+			return fmt.Errorf("failed to lock dependencies: %w", err)
+		}
+
+		var taskExists, blockerExists bool
+		query := `SELECT
+			EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND tenant_id = $3 AND deleted_at IS NULL),
+			EXISTS(SELECT 1 FROM tasks WHERE id = $2 AND tenant_id = $3 AND deleted_at IS NULL)`
+		if err := tx.QueryRow(ctx, query, taskID, blockerID, tenantID).Scan(&taskExists, &blockerExists); err != nil {
+			return fmt.Errorf("failed to check tasks: %w", err)
+		}
+		if err := dependencyError(taskID, blockerID, taskExists, blockerExists); err != nil {
+			return err
+		}
+
+		var cycle bool
+		query = `
+			WITH RECURSIVE upstream AS (
+				SELECT blocker_id FROM task_dependencies WHERE task_id = $1 AND tenant_id = $3
+				UNION
+				SELECT d.blocker_id
+				FROM task_dependencies d
+				JOIN upstream u ON d.task_id = u.blocker_id
+			)
+			SELECT EXISTS(SELECT 1 FROM upstream WHERE blocker_id = $2)`
+		if err := tx.QueryRow(ctx, query, blockerID, taskID, tenantID).Scan(&cycle); err != nil {
+			return fmt.Errorf("failed to check dependency cycle: %w", err)
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		query = `INSERT INTO task_dependencies (task_id, blocker_id, tenant_id) VALUES ($1, $2, $3)
+			RETURNING created_at`
+		err := tx.QueryRow(ctx, query, taskID, blockerID, tenantID).Scan(&dep.CreatedAt)
+		if isUniqueViolation(err) {
+			return ErrDependencyExists
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add dependency: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dep, nil
+}
+
+// dependencyError explains why taskID cannot be blocked by blockerID, or
+// returns nil
+func dependencyError(taskID, blockerID string, taskExists, blockerExists bool) error {
+	switch {
+	case !taskExists:
+		return ErrTaskNotFound
+	case taskID == blockerID:
+		return fmt.Errorf("%w: a task cannot block itself", ErrInvalidDependency)
+	case !blockerExists:
+		return fmt.Errorf("%w: blocker task %s not found", ErrInvalidDependency, blockerID)
+	}
+	return nil
+}
+
+// Remove deletes a dependency
+func (r *PostgresDependencyRepository) Remove(ctx context.Context, taskID, blockerID string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND blocker_id = $2 AND tenant_id = $3`
+	result, err := r.pool.Exec(ctx, query, taskID, blockerID, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to remove dependency: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrDependencyNotFound
+	}
+
+	return nil
+}
+
+// Blockers returns the tasks blocking taskID, oldest first; blockers in the
+// trash no longer block
+func (r *PostgresDependencyRepository) Blockers(ctx context.Context, taskID string) ([]model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE tenant_id = $2 AND deleted_at IS NULL
+			AND id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = $1)
+		ORDER BY created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, taskID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blockers: %w", err)
+	}
+	return scanTasks(rows)
+}
+
+// Edges returns the dependencies between tasks that are all in ids
+func (r *PostgresDependencyRepository) Edges(ctx context.Context, ids []string) ([]model.TaskDependency, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT task_id, blocker_id, created_at FROM task_dependencies
+		WHERE tenant_id = $1 AND task_id = ANY($2) AND blocker_id = ANY($2)`
+
+	rows, err := r.pool.Query(ctx, query, tenantID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []model.TaskDependency
+	for rows.Next() {
+		var d model.TaskDependency
+		if err := rows.Scan(&d.TaskID, &d.BlockerID, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		deps = append(deps, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate dependencies: %w", err)
+	}
+
+	return deps, nil
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// PostgresProjectRepository handles PostgreSQL operations for projects
+type PostgresProjectRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresProjectRepository creates a new PostgreSQL project repository
+func NewPostgresProjectRepository(pool *pgxpool.Pool) *PostgresProjectRepository {
+	return &PostgresProjectRepository{pool: pool}
+}
+
+// projectColumns is the column list matching scanProject
+const projectColumns = `id, tenant_id, name, description, owner_id, archived, created_at, updated_at`
+
+func scanProject(row pgx.Row) (*model.Project, error) {
+	var p model.Project
+	if err := row.Scan(&p.ID, &p.TenantID, &p.Name, &p.Description, &p.OwnerID, &p.Archived, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// isForeignKeyViolation reports whether err is a PostgreSQL foreign_key_violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// Create inserts a new project
+func (r *PostgresProjectRepository) Create(ctx context.Context, req model.ProjectCreateRequest) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO projects (id, tenant_id, name, description, owner_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + projectColumns
+
+	project, err := scanProject(r.pool.QueryRow(ctx, query,
+		uuid.New().String(), tenantID, req.Name, req.Description, req.OwnerID, now, now,
+	))
+	if isUniqueViolation(err) {
+		return nil, ErrProjectExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return project, nil
+}
+
+// GetByID retrieves a project by ID
+func (r *PostgresProjectRepository) GetByID(ctx context.Context, id string) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1 AND tenant_id = $2`
+	project, err := scanProject(r.pool.QueryRow(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	return project, nil
+}
+
+// List retrieves one page of projects ordered by name, with the total count
+func (r *PostgresProjectRepository) List(ctx context.Context, page, perPage int, includeArchived bool) ([]model.Project, int, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := ` WHERE tenant_id = $1 AND ($2 OR NOT archived)`
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM projects`+where, tenantID, includeArchived).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count projects: %w", err)
+	}
+
+	query := `SELECT ` + projectColumns + ` FROM projects` + where + ` ORDER BY lower(name), id LIMIT $3 OFFSET $4`
+	rows, err := r.pool.Query(ctx, query, tenantID, includeArchived, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []model.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate projects: %w", err)
+	}
+
+	return projects, total, nil
+}
+
+// Update modifies the fields set in req
+func (r *PostgresProjectRepository) Update(ctx context.Context, id string, req model.ProjectUpdateRequest) (*model.Project, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE projects
+		SET name = COALESCE($1, name),
+			description = COALESCE($2, description),
+			owner_id = CASE WHEN $3::varchar IS NULL THEN owner_id ELSE NULLIF($3, '') END,
+			archived = COALESCE($4, archived),
+			updated_at = NOW()
+		WHERE id = $5 AND tenant_id = $6
+		RETURNING ` + projectColumns
+
+	project, err := scanProject(r.pool.QueryRow(ctx, query,
+		req.Name, req.Description, req.OwnerID, req.Archived, id, tenantID,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProjectNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrProjectExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return project, nil
+}
+
+// Delete removes a project; the tasks.project_id foreign key refuses while
+// tasks, including trashed ones, still reference it
+func (r *PostgresProjectRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.pool.Exec(ctx, `DELETE FROM projects WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if isForeignKeyViolation(err) {
+		return ErrProjectNotEmpty
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrProjectNotFound
+	}
+
+	return nil
+}
+
+// Summary aggregates the project's tasks in a single query. Percent complete
+// is the share of completed tasks among those not cancelled.
+func (r *PostgresProjectRepository) Summary(ctx context.Context, id string) (*model.ProjectSummary, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH counts AS (
+			SELECT status, priority, COUNT(*)::int AS n
+			FROM tasks
+			WHERE tenant_id = $1 AND project_id = $2 AND deleted_at IS NULL
+			GROUP BY status, priority
+		)
+		SELECT
+			EXISTS(SELECT 1 FROM projects WHERE id = $2 AND tenant_id = $1),
+			COALESCE((SELECT SUM(n) FROM counts), 0)::int,
+			COALESCE((SELECT jsonb_object_agg(status, n)
+				FROM (SELECT status, SUM(n)::int AS n FROM counts GROUP BY status) s), '{}'),
+			COALESCE((SELECT jsonb_object_agg(priority, n)
+				FROM (SELECT priority, SUM(n)::int AS n FROM counts GROUP BY priority) p), '{}'),
+			COALESCE((SELECT ROUND(100.0 * SUM(n) FILTER (WHERE status = 'completed')
+				/ NULLIF(SUM(n) FILTER (WHERE status <> 'cancelled'), 0), 1) FROM counts), 0)::float8`
+
+	summary := model.ProjectSummary{ProjectID: id}
+	var exists bool
+	err = r.pool.QueryRow(ctx, query, tenantID, id).Scan(
+		&exists, &summary.Total, &summary.ByStatus, &summary.ByPriority, &summary.PercentComplete,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize project: %w", err)
+	}
+	if !exists {
+		return nil, ErrProjectNotFound
+	}
+
+	summary.FillZeroCounts()
+	return &summary, nil
+}
+
+// ReleaseOwner clears the user's owner references in the tenant
+func (r *PostgresProjectRepository) ReleaseOwner(ctx context.Context, userID string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.pool.Exec(ctx, `UPDATE projects SET owner_id = NULL WHERE owner_id = $1 AND tenant_id = $2`,
+		userID, tenantID); err != nil {
+		return fmt.Errorf("failed to release project owner: %w", err)
+	}
+	return nil
+}
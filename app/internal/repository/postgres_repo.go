@@ -2,16 +2,24 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/hamfa/task-manager/internal/model"
 )
 
-// PostgresRepository handles PostgreSQL operations for tasks
+// PostgresRepository handles PostgreSQL operations for tasks. Every query is
+// limited to the tenant of its context, except the cross-tenant background
+// operations ClaimReminders and PurgeTrash. Trashed tasks have
+// deleted_at set and are skipped by every query not about the trash.
 type PostgresRepository struct {
 	pool *pgxpool.Pool
 }
@@ -21,35 +29,192 @@ func NewPostgresRepository(pool *pgxpool.Pool) *PostgresRepository {
 	return &PostgresRepository{pool: pool}
 }
 
-// InitSchema creates the tasks table if it doesn't exist
-func (r *PostgresRepository) InitSchema(ctx context.Context) error {
+// taskColumns is the column list matching scanTask. Tags are read with a
+// subquery; in a RETURNING clause it sees the tags from before the statement.
+const taskColumns = `id, tenant_id, title, description, status, priority, start_at, due_at, assignee_id, reporter_id, project_id, parent_id, version, created_at, updated_at, deleted_at, ` + taskTagsExpr
+
+// taskTagsExpr selects the sorted tag names of the task row
+const taskTagsExpr = `ARRAY(SELECT g.name FROM task_tags tt JOIN tags g ON g.id = tt.tag_id
+	WHERE tt.task_id = tasks.id ORDER BY g.name)`
+
+// scanTask scans a row selected with taskColumns, followed by any extra columns
+func scanTask(row pgx.Row, extra ...interface{}) (*model.Task, error) {
+	var t model.Task
+	dest := []interface{}{&t.ID, &t.TenantID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.StartAt, &t.DueAt, &t.AssigneeID, &t.ReporterID, &t.ProjectID, &t.ParentID, &t.Version, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt, &t.Tags}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// scanTasks reads every row of a query selecting taskColumns
+func scanTasks(rows pgx.Rows) ([]model.Task, error) {
+	defer rows.Close()
+
+	var tasks []model.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// whereClause joins conditions with AND, or returns "" when there are none
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+// reverseTasks reverses tasks in place
+func reverseTasks(tasks []model.Task) {
+	for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+		tasks[i], tasks[j] = tasks[j], tasks[i]
+	}
+}
+
+// dbtx is the query interface shared by *pgxpool.Pool and pgx.Tx. Begin
+// starts a transaction on a pool and a savepoint in a transaction.
+type dbtx interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// setTaskTags replaces the tags of a task, creating missing tags of the tenant
+func setTaskTags(ctx context.Context, db dbtx, tenantID, taskID string, tags []string) error {
+	if _, err := db.Exec(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID); err != nil {
+		return fmt.Errorf("failed to clear task tags: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO tags (tenant_id, name) SELECT $1, unnest($2::text[])
+		ON CONFLICT (tenant_id, name) DO NOTHING`
+	if _, err := db.Exec(ctx, query, tenantID, tags); err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	query = `INSERT INTO task_tags (task_id, tag_id)
+		SELECT $1, id FROM tags WHERE tenant_id = $2 AND name = ANY($3)`
+	if _, err := db.Exec(ctx, query, taskID, tenantID, tags); err != nil {
+		return fmt.Errorf("failed to tag task: %w", err)
+	}
+	return nil
+}
+
+// writeTagged runs write and, when tags is non-nil, replaces the tags of the
+// written task in the same transaction. Without tags, write runs on db as is.
+func writeTagged(ctx context.Context, db dbtx, tenantID string, tags *[]string, write func(dbtx) (*model.Task, error)) (*model.Task, error) {
+	if tags == nil {
+		return write(db)
+	}
+
+	var task *model.Task
+	err := pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		var err error
+		if task, err = write(tx); err != nil {
+			return err
+		}
+		task.Tags = *tags
+		return setTaskTags(ctx, tx, tenantID, task.ID, *tags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// sameTags reports whether two sorted tag lists are equal
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkParent verifies that parentID may become the parent of taskID (empty
+// for a new task). It walks the ancestors of parentID with a recursive CTE
+// bounded by model.MaxTaskDepth; a parent in the trash counts as unknown.
+func checkParent(ctx context.Context, db dbtx, tenantID, taskID string, parentID *string) error {
+	if parentID == nil || *parentID == "" {
+		return nil
+	}
+
 	query := `
-		CREATE TABLE IF NOT EXISTS tasks (
-			id VARCHAR(36) PRIMARY KEY,
-			title VARCHAR(255) NOT NULL,
-			description TEXT DEFAULT '',
-			status VARCHAR(20) DEFAULT 'pending',
-			priority VARCHAR(20) DEFAULT 'medium',
-			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-		);
-
-		CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
-		CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
-		CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
-	`
-	_, err := r.pool.Exec(ctx, query)
-	return err
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 1 AS depth
+			FROM tasks
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, t.parent_id, a.depth + 1
+			FROM tasks t
+			JOIN ancestors a ON t.id = a.parent_id
+			WHERE a.depth <= $4
+		)
+		SELECT COUNT(*)::int, COALESCE(BOOL_OR(id = $3), false) FROM ancestors`
+
+	var depth int
+	var cycle bool
+	if err := db.QueryRow(ctx, query, *parentID, tenantID, taskID, model.MaxTaskDepth).Scan(&depth, &cycle); err != nil {
+		return fmt.Errorf("failed to check parent task: %w", err)
+	}
+	return parentError(*parentID, depth, cycle)
+}
+
+// parentError explains why a parent with depth ancestors (counting itself) is
+// invalid, or returns nil; cycle reports that the child is among them
+func parentError(parentID string, depth int, cycle bool) error {
+	switch {
+	case depth == 0:
+		return fmt.Errorf("%w: parent task %s not found", ErrInvalidParent, parentID)
+	case cycle:
+		return fmt.Errorf("%w: a task cannot be a subtask of itself or of its subtasks", ErrInvalidParent)
+	case depth > model.MaxTaskDepth:
+		return fmt.Errorf("%w: subtasks nest at most %d levels deep", ErrInvalidParent, model.MaxTaskDepth)
+	}
+	return nil
 }
 
 // Create inserts a new task
 func (r *PostgresRepository) Create(ctx context.Context, req model.TaskCreateRequest) (*model.Task, error) {
+	return createTask(ctx, r.pool, req)
+}
+
+func createTask(ctx context.Context, db dbtx, req model.TaskCreateRequest) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &model.Task{
 		ID:          uuid.New().String(),
+		TenantID:    tenantID,
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      "pending",
 		Priority:    req.Priority,
+		StartAt:     req.StartAt,
+		DueAt:       req.DueAt,
+		AssigneeID:  req.AssigneeID,
+		ReporterID:  req.ReporterID,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+		Tags:        req.Tags,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -57,150 +222,912 @@ func (r *PostgresRepository) Create(ctx context.Context, req model.TaskCreateReq
 	if task.Priority == "" {
 		task.Priority = "medium"
 	}
+	if err := checkParent(ctx, db, tenantID, "", task.ParentID); err != nil {
+		return nil, err
+	}
 
 	query := `
-		INSERT INTO tasks (id, title, description, status, priority, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, title, description, status, priority, created_at, updated_at
-	`
+		INSERT INTO tasks (id, tenant_id, title, description, status, priority, start_at, due_at,
+			assignee_id, reporter_id, project_id, parent_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING ` + taskColumns
 
-	err := r.pool.QueryRow(ctx, query,
-		task.ID, task.Title, task.Description, task.Status,
-		task.Priority, task.CreatedAt, task.UpdatedAt,
-	).Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status,
-		&task.Priority, &task.CreatedAt, &task.UpdatedAt,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+	var tags *[]string
+	if len(req.Tags) > 0 {
+		tags = &req.Tags
 	}
 
-	return task, nil
+	return writeTagged(ctx, db, tenantID, tags, func(db dbtx) (*model.Task, error) {
+		created, err := scanTask(db.QueryRow(ctx, query,
+			task.ID, task.TenantID, task.Title, task.Description, task.Status, task.Priority,
+			task.StartAt, task.DueAt, task.AssigneeID, task.ReporterID, task.ProjectID, task.ParentID,
+			task.CreatedAt, task.UpdatedAt,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create task: %w", err)
+		}
+		return created, nil
+	})
 }
 
 // GetByID retrieves a task by its ID
 func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*model.Task, error) {
-	query := `SELECT id, title, description, status, priority, created_at, updated_at FROM tasks WHERE id = $1`
-
-	var task model.Task
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status,
-		&task.Priority, &task.CreatedAt, &task.UpdatedAt,
-	)
+	tenantID, err := scopedTenant(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("task not found: %w", err)
+		return nil, err
 	}
 
-	return &task, nil
-}
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL`
 
-// List retrieves paginated tasks
-func (r *PostgresRepository) List(ctx context.Context, page, perPage int, status string) ([]model.Task, int, error) {
-	if page < 1 {
-		page = 1
+	task, err := scanTask(r.pool.QueryRow(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTaskNotFound
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 20
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
-	offset := (page - 1) * perPage
 
-	// Count total
-	countQuery := `SELECT COUNT(*) FROM tasks`
-	args := []interface{}{}
+	return task, nil
+}
+
+// priorityRankExpr orders priorities semantically rather than alphabetically
+const priorityRankExpr = `CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'critical' THEN 4 ELSE 0 END`
+
+// Full-text search expressions; they reference the "query" tsquery joined in List.
+// The snippet delimits matches with control characters so that the text can
+// be HTML-escaped before they are turned into <mark> tags (see highlightSnippet).
+const (
+	searchRankExpr    = `ts_rank(search_vector, query)::float8`
+	searchSnippetExpr = `ts_headline('english', title || ' ' || coalesce(description, ''), query,
+		'StartSel="' || chr(2) || '", StopSel="' || chr(3) || '", MaxWords=35, MinWords=15, MaxFragments=2')`
+)
+
+// snippetMarks turns the match delimiters of searchSnippetExpr into <mark> tags
+var snippetMarks = strings.NewReplacer("\x02", "<mark>", "\x03", "</mark>")
+
+// highlightSnippet HTML-escapes a snippet from searchSnippetExpr, whose text
+// comes straight from the task, and marks its matches
+func highlightSnippet(snippet string) string {
+	return snippetMarks.Replace(html.EscapeString(snippet))
+}
 
-	if status != "" {
-		countQuery += ` WHERE status = $1`
-		args = append(args, status)
+// sortColumn maps a sort field to its SQL expression and the pivot's key value
+func sortColumn(sort string, pivot model.Task) (string, interface{}) {
+	switch sort {
+	case model.SortUpdatedAt:
+		return "updated_at", pivot.UpdatedAt
+	case model.SortPriority:
+		return priorityRankExpr, model.PriorityRank(pivot.Priority)
+	case model.SortTitle:
+		return "title", pivot.Title
+	case model.SortRelevance:
+		return searchRankExpr, pivot.Rank
+	default:
+		return "created_at", pivot.CreatedAt
 	}
+}
+
+// List retrieves one page of filtered, sorted tasks.
+// A cursor switches from LIMIT/OFFSET to keyset pagination on (sort key, id).
+func (r *PostgresRepository) List(ctx context.Context, q model.TaskListQuery) (*model.TaskPage, error) {
+	q.Normalize()
 
-	var total int
-	err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&total)
+	tenantID, err := scopedTenant(ctx)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count tasks: %w", err)
+		return nil, err
 	}
 
-	// Fetch tasks
-	listQuery := `SELECT id, title, description, status, priority, created_at, updated_at FROM tasks`
-	if status != "" {
-		listQuery += ` WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
-		args = append(args, perPage, offset)
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where = append(where, "tenant_id = "+arg(tenantID))
+	if q.Filter.Trashed {
+		where = append(where, "deleted_at IS NOT NULL")
 	} else {
-		listQuery += ` ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-		args = append(args, perPage, offset)
+		where = append(where, "deleted_at IS NULL")
 	}
 
+	from := ` FROM tasks`
+	columns := taskColumns
+
+	f := q.Filter
+	if f.Search != "" {
+		from += `, websearch_to_tsquery('english', ` + arg(f.Search) + `) AS query`
+		where = append(where, "search_vector @@ query")
+		columns += ", " + searchRankExpr + ", " + searchSnippetExpr
+	}
+	if len(f.IDs) > 0 {
+		where = append(where, "id = ANY("+arg(f.IDs)+")")
+	}
+	if len(f.Statuses) > 0 {
+		where = append(where, "status = ANY("+arg(f.Statuses)+")")
+	}
+	if len(f.Priorities) > 0 {
+		where = append(where, "priority = ANY("+arg(f.Priorities)+")")
+	}
+	if f.CreatedAfter != nil {
+		where = append(where, "created_at >= "+arg(*f.CreatedAfter))
+	}
+	if f.CreatedBefore != nil {
+		where = append(where, "created_at < "+arg(*f.CreatedBefore))
+	}
+	if f.UpdatedAfter != nil {
+		where = append(where, "updated_at >= "+arg(*f.UpdatedAfter))
+	}
+	if f.UpdatedBefore != nil {
+		where = append(where, "updated_at < "+arg(*f.UpdatedBefore))
+	}
+	if f.DueAfter != nil {
+		where = append(where, "due_at >= "+arg(*f.DueAfter))
+	}
+	if f.DueBefore != nil {
+		where = append(where, "due_at < "+arg(*f.DueBefore))
+	}
+	if len(f.AssigneeIDs) > 0 {
+		where = append(where, "assignee_id = ANY("+arg(f.AssigneeIDs)+")")
+	}
+	if len(f.ReporterIDs) > 0 {
+		where = append(where, "reporter_id = ANY("+arg(f.ReporterIDs)+")")
+	}
+	if len(f.ProjectIDs) > 0 {
+		where = append(where, "project_id = ANY("+arg(f.ProjectIDs)+")")
+	}
+	if len(f.ParentIDs) > 0 {
+		where = append(where, "parent_id = ANY("+arg(f.ParentIDs)+")")
+	}
+	if len(f.TagsAny) > 0 {
+		where = append(where, `EXISTS(SELECT 1 FROM task_tags tt JOIN tags g ON g.id = tt.tag_id
+			WHERE tt.task_id = tasks.id AND g.name = ANY(`+arg(f.TagsAny)+`))`)
+	}
+	if len(f.TagsAll) > 0 {
+		where = append(where, `(SELECT COUNT(*) FROM task_tags tt JOIN tags g ON g.id = tt.tag_id
+			WHERE tt.task_id = tasks.id AND g.name = ANY(`+arg(f.TagsAll)+`)) = `+arg(len(f.TagsAll)))
+	}
+	if f.OpenOnly {
+		where = append(where, "status <> ALL("+arg(model.ClosedStatuses)+")")
+	}
+
+	page := &model.TaskPage{}
+
+	// Count total (optional — skipping it avoids a full scan per request)
+	if q.IncludeTotal {
+		countQuery := `SELECT COUNT(*)` + from + whereClause(where)
+		var total int
+		if err := r.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count tasks: %w", err)
+		}
+		page.Total = &total
+	}
+
+	// A backward scan walks the ordering in reverse, then flips the rows back
+	backward := q.Cursor != nil && q.Cursor.Backward
+	ascending := q.Order == model.OrderAsc
+	if backward {
+		ascending = !ascending
+	}
+	dir, cmp := "DESC", "<"
+	if ascending {
+		dir, cmp = "ASC", ">"
+	}
+
+	var pivot model.Task
+	if q.Cursor != nil {
+		p, err := q.Cursor.Pivot()
+		if err != nil {
+			return nil, err
+		}
+		pivot = p
+	}
+	sortExpr, sortValue := sortColumn(q.Sort, pivot)
+
+	if q.Cursor != nil {
+		where = append(where, fmt.Sprintf("(%s, id) %s (%s, %s)", sortExpr, cmp, arg(sortValue), arg(pivot.ID)))
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	listQuery := `SELECT ` + columns + from + whereClause(where) +
+		fmt.Sprintf(` ORDER BY %s %s, id %s`, sortExpr, dir, dir) +
+		` LIMIT ` + arg(q.PerPage+1) + ` OFFSET ` + arg(q.Offset())
+
 	rows, err := r.pool.Query(ctx, listQuery, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list tasks: %w", err)
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
 	defer rows.Close()
 
-	var tasks []model.Task
 	for rows.Next() {
-		var t model.Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Status, &t.Priority, &t.CreatedAt, &t.UpdatedAt); err != nil {
-			return nil, 0, fmt.Errorf("failed to scan task: %w", err)
+		var rank float64
+		var snippet string
+		var extra []interface{}
+		if f.Search != "" {
+			extra = []interface{}{&rank, &snippet}
+		}
+
+		t, err := scanTask(rows, extra...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		tasks = append(tasks, t)
+		t.Rank, t.Snippet = rank, highlightSnippet(snippet)
+		page.Tasks = append(page.Tasks, *t)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	if len(page.Tasks) > q.PerPage {
+		page.Tasks = page.Tasks[:q.PerPage]
+		page.HasMore = true
+	}
+	if backward {
+		reverseTasks(page.Tasks)
+	}
+
+	return page, nil
+}
 
-	return tasks, total, nil
+// Update modifies an existing task in a single statement, plus a transaction
+// around it when the tags change. When expectedVersion is non-zero the update
+// only applies if the stored version still matches.
+func (r *PostgresRepository) Update(ctx context.Context, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error) {
+	return updateTask(ctx, r.pool, id, req, expectedVersion)
 }
 
-// Update modifies an existing task
-func (r *PostgresRepository) Update(ctx context.Context, id string, req model.TaskUpdateRequest) (*model.Task, error) {
-	// First get the existing task
-	existing, err := r.GetByID(ctx, id)
+func updateTask(ctx context.Context, db dbtx, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkParent(ctx, db, tenantID, id, req.ParentID); err != nil {
+		return nil, err
+	}
 
-	if req.Title != nil {
-		existing.Title = *req.Title
+	query := `
+		UPDATE tasks
+		SET title = COALESCE($1, title),
+			description = COALESCE($2, description),
+			status = COALESCE($3, status),
+			priority = COALESCE($4, priority),
+			start_at = COALESCE($5, start_at),
+			due_at = COALESCE($6, due_at),
+			assignee_id = CASE WHEN $7::varchar IS NULL THEN assignee_id ELSE NULLIF($7, '') END,
+			reporter_id = CASE WHEN $8::varchar IS NULL THEN reporter_id ELSE NULLIF($8, '') END,
+			project_id = CASE WHEN $9::varchar IS NULL THEN project_id ELSE NULLIF($9, '') END,
+			parent_id = CASE WHEN $10::varchar IS NULL THEN parent_id ELSE NULLIF($10, '') END,
+			version = version + 1,
+			updated_at = NOW()
+		WHERE id = $11 AND ($12::int = 0 OR version = $12) AND tenant_id = $13 AND deleted_at IS NULL
+		RETURNING ` + taskColumns
+
+	return writeTagged(ctx, db, tenantID, req.Tags, func(db dbtx) (*model.Task, error) {
+		task, err := scanTask(db.QueryRow(ctx, query,
+			req.Title, req.Description, req.Status, req.Priority,
+			req.StartAt, req.DueAt, req.AssigneeID, req.ReporterID, req.ProjectID, req.ParentID,
+			id, expectedVersion, tenantID,
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, missOrConflict(ctx, db, id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+		return task, nil
+	})
+}
+
+// Delete moves a task to the trash, subject to the same version check as
+// Update, and handles its subtasks in the same transaction
+func (r *PostgresRepository) Delete(ctx context.Context, id string, expectedVersion int, onSubtasks string) ([]model.Task, error) {
+	_, subtasks, err := trashTask(ctx, r.pool, id, expectedVersion, onSubtasks)
+	return subtasks, err
+}
+
+// subtreeCTE selects the IDs of the subtasks of $1, trashed or not, down to
+// $3 levels deep
+const subtreeCTE = `
+	WITH RECURSIVE subtree AS (
+		SELECT id AS node_id, 1 AS depth
+		FROM tasks
+		WHERE parent_id = $1 AND tenant_id = $2
+		UNION ALL
+		SELECT t.id, s.depth + 1
+		FROM tasks t
+		JOIN subtree s ON t.parent_id = s.node_id
+		WHERE s.depth < $3
+	)`
+
+// trashTask locks a task and moves it to the trash, applying the onSubtasks
+// policy, and returns the trashed row with the subtasks it trashed or
+// detached. Subtasks trashed with the task share its deleted_at, which is how
+// Restore finds them again.
+func trashTask(ctx context.Context, db dbtx, id string, expectedVersion int, onSubtasks string) (*model.Task, []model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	if req.Description != nil {
-		existing.Description = *req.Description
+
+	var task *model.Task
+	var subtasks []model.Task
+	err = pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		var version int
+		query := `SELECT version FROM tasks WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE`
+		err := tx.QueryRow(ctx, query, id, tenantID).Scan(&version)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock task: %w", err)
+		}
+		if expectedVersion != 0 && version != expectedVersion {
+			return ErrVersionConflict
+		}
+
+		switch onSubtasks {
+		case model.SubtasksCascade:
+			rows, err := tx.Query(ctx, subtreeCTE+`
+				UPDATE tasks SET deleted_at = NOW(), version = version + 1
+				WHERE id IN (SELECT node_id FROM subtree) AND deleted_at IS NULL
+				RETURNING `+taskColumns, id, tenantID, model.MaxTaskDepth)
+			if err != nil {
+				return fmt.Errorf("failed to trash subtasks: %w", err)
+			}
+			if subtasks, err = scanTasks(rows); err != nil {
+				return err
+			}
+		case model.SubtasksOrphan:
+			rows, err := tx.Query(ctx, `
+				UPDATE tasks SET parent_id = NULL, version = version + 1, updated_at = NOW()
+				WHERE parent_id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+				RETURNING `+taskColumns, id, tenantID)
+			if err != nil {
+				return fmt.Errorf("failed to detach subtasks: %w", err)
+			}
+			if subtasks, err = scanTasks(rows); err != nil {
+				return err
+			}
+		default:
+			var has bool
+			query := `SELECT EXISTS(SELECT 1 FROM tasks WHERE parent_id = $1 AND deleted_at IS NULL)`
+			if err := tx.QueryRow(ctx, query, id).Scan(&has); err != nil {
+				return fmt.Errorf("failed to check subtasks: %w", err)
+			}
+			if has {
+				return ErrHasSubtasks
+			}
+		}
+
+		// Not an edit: the trigger leaves updated_at alone (migration 019)
+		query = `UPDATE tasks SET deleted_at = NOW(), version = version + 1 WHERE id = $1 RETURNING ` + taskColumns
+		task, err = scanTask(tx.QueryRow(ctx, query, id))
+		if err != nil {
+			return fmt.Errorf("failed to trash task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	if req.Status != nil {
-		existing.Status = *req.Status
+	return task, subtasks, nil
+}
+
+// Restore takes a task out of the trash together with the subtasks that were
+// trashed with it, in one transaction
+func (r *PostgresRepository) Restore(ctx context.Context, id string) (*model.Task, []model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	if req.Priority != nil {
-		existing.Priority = *req.Priority
+
+	var task *model.Task
+	var subtasks []model.Task
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var deletedAt time.Time
+		var parentID *string
+		query := `SELECT deleted_at, parent_id FROM tasks
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL FOR UPDATE`
+		err := tx.QueryRow(ctx, query, id, tenantID).Scan(&deletedAt, &parentID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock task: %w", err)
+		}
+
+		if parentID != nil {
+			var parentTrashed bool
+			query = `SELECT deleted_at IS NOT NULL FROM tasks WHERE id = $1 FOR SHARE`
+			if err := tx.QueryRow(ctx, query, *parentID).Scan(&parentTrashed); err != nil {
+				return fmt.Errorf("failed to check parent task: %w", err)
+			}
+			if parentTrashed {
+				return ErrParentInTrash
+			}
+		}
+
+		rows, err := tx.Query(ctx, `
+			WITH RECURSIVE subtree AS (
+				SELECT id AS node_id, 1 AS depth
+				FROM tasks
+				WHERE parent_id = $1 AND tenant_id = $2 AND deleted_at = $3
+				UNION ALL
+				SELECT t.id, s.depth + 1
+				FROM tasks t
+				JOIN subtree s ON t.parent_id = s.node_id
+				WHERE t.deleted_at = $3 AND s.depth < $4
+			)
+			UPDATE tasks SET deleted_at = NULL, version = version + 1
+			WHERE id IN (SELECT node_id FROM subtree)
+			RETURNING `+taskColumns, id, tenantID, deletedAt, model.MaxTaskDepth)
+		if err != nil {
+			return fmt.Errorf("failed to restore subtasks: %w", err)
+		}
+		if subtasks, err = scanTasks(rows); err != nil {
+			return err
+		}
+
+		query = `UPDATE tasks SET deleted_at = NULL, version = version + 1 WHERE id = $1 RETURNING ` + taskColumns
+		task, err = scanTask(tx.QueryRow(ctx, query, id))
+		if err != nil {
+			return fmt.Errorf("failed to restore task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return task, subtasks, nil
+}
+
+// Purge deletes a trashed task and its trashed subtasks in one transaction.
+// Foreign keys remove their tags, dependencies and attachment metadata.
+func (r *PostgresRepository) Purge(ctx context.Context, id string) (*model.Task, []model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var task *model.Task
+	var subtasks []model.Task
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var exists bool
+		query := `SELECT true FROM tasks WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NOT NULL FOR UPDATE`
+		err := tx.QueryRow(ctx, query, id, tenantID).Scan(&exists)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock task: %w", err)
+		}
+
+		rows, err := tx.Query(ctx, subtreeCTE+`
+			DELETE FROM tasks WHERE id IN (SELECT node_id FROM subtree) AND deleted_at IS NOT NULL
+			RETURNING `+taskColumns, id, tenantID, model.MaxTaskDepth)
+		if err != nil {
+			return fmt.Errorf("failed to purge subtasks: %w", err)
+		}
+		if subtasks, err = scanTasks(rows); err != nil {
+			return err
+		}
+
+		task, err = scanTask(tx.QueryRow(ctx, `DELETE FROM tasks WHERE id = $1 RETURNING `+taskColumns, id))
+		if err != nil {
+			return fmt.Errorf("failed to purge task: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	existing.UpdatedAt = time.Now()
+	return task, subtasks, nil
+}
 
+// PurgeTrash deletes up to limit tasks trashed before cutoff, oldest first,
+// skipping rows another server is purging. It works across tenants. A
+// subtask left for a later call loses its parent_id with the parent.
+func (r *PostgresRepository) PurgeTrash(ctx context.Context, cutoff time.Time, limit int) ([]model.Task, error) {
 	query := `
+		DELETE FROM tasks
+		WHERE id IN (
+			SELECT id FROM tasks
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + taskColumns
+
+	rows, err := r.pool.Query(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to purge trash: %w", err)
+	}
+	return scanTasks(rows)
+}
+
+// Batch applies ops in order. In atomic mode they share one transaction that
+// is rolled back on the first failure.
+func (r *PostgresRepository) Batch(ctx context.Context, ops []TaskBatchOp, atomic bool) ([]TaskBatchResult, error) {
+	results := make([]TaskBatchResult, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			if op.Kind == model.BatchOpUpdate && op.Modify != nil {
+				// Read-modify-write needs its own transaction for the row lock
+				results[i].Task, results[i].Err = r.Modify(ctx, op.ID, op.ExpectedVersion, op.Modify)
+				continue
+			}
+			results[i].Task, results[i].Subtasks, results[i].Err = applyBatchOp(ctx, r.pool, op)
+		}
+		return results, nil
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for i, op := range ops {
+		task, subtasks, err := applyBatchOp(ctx, tx, op)
+		if err != nil {
+			abortBatch(results, i, err)
+			return results, nil
+		}
+		results[i].Task, results[i].Subtasks = task, subtasks
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit batch: %w", err)
+	}
+	return results, nil
+}
+
+func applyBatchOp(ctx context.Context, db dbtx, op TaskBatchOp) (*model.Task, []model.Task, error) {
+	var task *model.Task
+	var err error
+	switch op.Kind {
+	case model.BatchOpCreate:
+		task, err = createTask(ctx, db, op.Create)
+	case model.BatchOpUpdate:
+		if op.Modify != nil {
+			task, err = modifyTask(ctx, db, op.ID, op.ExpectedVersion, op.Modify)
+		} else {
+			task, err = updateTask(ctx, db, op.ID, op.Update, op.ExpectedVersion)
+		}
+	case model.BatchOpDelete:
+		return trashTask(ctx, db, op.ID, op.ExpectedVersion, op.OnSubtasks)
+	default:
+		err = fmt.Errorf("unknown batch operation %q", op.Kind)
+	}
+	return task, nil, err
+}
+
+// Modify locks the task row, applies fn and writes the result in one transaction
+func (r *PostgresRepository) Modify(ctx context.Context, id string, expectedVersion int, fn func(*model.Task) error) (*model.Task, error) {
+	var updated *model.Task
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		updated, err = modifyTask(ctx, tx, id, expectedVersion, fn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// modifyTask is the body of Modify; db must be a transaction
+func modifyTask(ctx context.Context, db dbtx, id string, expectedVersion int, fn func(*model.Task) error) (*model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL FOR UPDATE`
+	task, err := scanTask(db.QueryRow(ctx, query, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock task: %w", err)
+	}
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return nil, ErrVersionConflict
+	}
+
+	tags, parentID := task.Tags, task.ParentID
+	if err := fn(task); err != nil {
+		return nil, err
+	}
+	if task.ParentID != nil && (parentID == nil || *parentID != *task.ParentID) {
+		if err := checkParent(ctx, db, tenantID, id, task.ParentID); err != nil {
+			return nil, err
+		}
+	}
+	if !sameTags(tags, task.Tags) {
+		if err := setTaskTags(ctx, db, tenantID, id, task.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	query = `
 		UPDATE tasks
-		SET title = $1, description = $2, status = $3, priority = $4, updated_at = $5
-		WHERE id = $6
-		RETURNING id, title, description, status, priority, created_at, updated_at
-	`
-
-	var task model.Task
-	err = r.pool.QueryRow(ctx, query,
-		existing.Title, existing.Description, existing.Status,
-		existing.Priority, existing.UpdatedAt, id,
-	).Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status,
-		&task.Priority, &task.CreatedAt, &task.UpdatedAt,
-	)
+		SET title = $1, description = $2, status = $3, priority = $4,
+			start_at = $5, due_at = $6, assignee_id = $7, reporter_id = $8, project_id = $9,
+			parent_id = $10, version = version + 1, updated_at = NOW()
+		WHERE id = $11
+		RETURNING ` + taskColumns
+
+	updated, err := scanTask(db.QueryRow(ctx, query,
+		task.Title, task.Description, task.Status, task.Priority,
+		task.StartAt, task.DueAt, task.AssigneeID, task.ReporterID, task.ProjectID, task.ParentID, id,
+	))
 	if err != nil {
 		return nil, fmt.Errorf("failed to update task: %w", err)
 	}
+	updated.Tags = task.Tags
+	return updated, nil
+}
 
-	return &task, nil
+// reminderColumns maps a reminder kind to the column recording the due date
+// it was last sent for
+var reminderColumns = map[string]string{
+	model.ReminderDueSoon: "due_soon_reminded_for",
+	model.ReminderOverdue: "overdue_reminded_for",
 }
 
-// Delete removes a task by ID
-func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
-	result, err := r.pool.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, id)
+// ClaimReminders locks the matching rows (skipping rows another server is
+// processing), hands them to fn and marks them reminded in one transaction.
+// It works across tenants.
+func (r *PostgresRepository) ClaimReminders(ctx context.Context, kind string, now time.Time, window time.Duration, limit int, fn func([]model.Task) error) error {
+	column, ok := reminderColumns[kind]
+	if !ok {
+		return fmt.Errorf("unknown reminder kind %q", kind)
+	}
+
+	args := []interface{}{model.ClosedStatuses, limit, now}
+	due := "due_at < $3"
+	if kind == model.ReminderDueSoon {
+		args = append(args, now.Add(window))
+		due = "due_at >= $3 AND due_at < $4"
+	}
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE ` + due + `
+			AND status <> ALL($1)
+			AND deleted_at IS NULL
+			AND ` + column + ` IS DISTINCT FROM due_at
+		ORDER BY due_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to query reminders: %w", err)
+		}
+		defer rows.Close()
+
+		var tasks []model.Task
+		var ids []string
+		for rows.Next() {
+			t, err := scanTask(rows)
+			if err != nil {
+				return fmt.Errorf("failed to scan task: %w", err)
+			}
+			tasks = append(tasks, *t)
+			ids = append(ids, t.ID)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to iterate reminders: %w", err)
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		if err := fn(tasks); err != nil {
+			return err
+		}
+
+		// Bookkeeping only: the trigger leaves updated_at alone (migration 018)
+		if _, err := tx.Exec(ctx, `UPDATE tasks SET `+column+` = due_at WHERE id = ANY($1)`, ids); err != nil {
+			return fmt.Errorf("failed to mark reminders: %w", err)
+		}
+		return nil
+	})
+}
+
+// ReleaseUser clears the user's assignee and reporter references in the
+// tenant in one statement
+func (r *PostgresRepository) ReleaseUser(ctx context.Context, userID string) ([]ReleasedTask, error) {
+	tenantID, err := scopedTenant(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+		return nil, err
 	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("task not found")
+
+	query := `
+		WITH released AS (
+			SELECT id AS task_id, assignee_id = $1 AS was_assignee
+			FROM tasks
+			WHERE tenant_id = $2 AND (assignee_id = $1 OR reporter_id = $1)
+			FOR UPDATE
+		)
+		UPDATE tasks
+		SET assignee_id = CASE WHEN assignee_id = $1 THEN NULL ELSE assignee_id END,
+			reporter_id = CASE WHEN reporter_id = $1 THEN NULL ELSE reporter_id END,
+			version = version + 1,
+			updated_at = NOW()
+		FROM released
+		WHERE id = released.task_id
+		RETURNING ` + taskColumns + `, released.was_assignee`
+
+	rows, err := r.pool.Query(ctx, query, userID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release user: %w", err)
 	}
-	return nil
+	defer rows.Close()
+
+	var released []ReleasedTask
+	for rows.Next() {
+		var wasAssignee bool
+		t, err := scanTask(rows, &wasAssignee)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		released = append(released, ReleasedTask{Task: *t, WasAssignee: wasAssignee})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tasks: %w", err)
+	}
+
+	return released, nil
+}
+
+// TagCounts counts the tasks carrying each tag of the tenant, most used first
+func (r *PostgresRepository) TagCounts(ctx context.Context) ([]model.TagCount, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT g.name, COUNT(*)::int
+		FROM tags g
+		JOIN task_tags tt ON tt.tag_id = g.id
+		JOIN tasks t ON t.id = tt.task_id
+		WHERE g.tenant_id = $1 AND t.deleted_at IS NULL
+		GROUP BY g.name
+		ORDER BY COUNT(*) DESC, g.name`
+
+	rows, err := r.pool.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []model.TagCount
+	for rows.Next() {
+		var tc model.TagCount
+		if err := rows.Scan(&tc.Name, &tc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, tc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tag counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// Subtree returns a task and its subtasks down to model.MaxTaskDepth levels,
+// ordered by depth and then creation
+func (r *PostgresRepository) Subtree(ctx context.Context, id string) ([]model.Task, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT id AS node_id, 0 AS depth
+			FROM tasks
+			WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, s.depth + 1
+			FROM tasks t
+			JOIN subtree s ON t.parent_id = s.node_id
+			WHERE t.deleted_at IS NULL AND s.depth < $3
+		)
+		SELECT ` + taskColumns + `
+		FROM tasks
+		JOIN subtree ON subtree.node_id = tasks.id
+		ORDER BY subtree.depth, created_at, id`
+
+	rows, err := r.pool.Query(ctx, query, id, tenantID, model.MaxTaskDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subtree: %w", err)
+	}
+	tasks, err := scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, ErrTaskNotFound
+	}
+
+	return tasks, nil
+}
+
+// SubtaskProgress counts the subtasks below each of ids, at every depth, in a
+// single recursive query
+func (r *PostgresRepository) SubtaskProgress(ctx context.Context, ids []string) (map[string]model.TaskProgress, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH RECURSIVE subtree AS (
+			SELECT parent_id AS root_id, id AS node_id, status, 1 AS depth
+			FROM tasks
+			WHERE parent_id = ANY($1) AND tenant_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT s.root_id, t.id, t.status, s.depth + 1
+			FROM tasks t
+			JOIN subtree s ON t.parent_id = s.node_id
+			WHERE t.deleted_at IS NULL AND s.depth < $3
+		)
+		SELECT root_id, COUNT(*)::int,
+			COUNT(*) FILTER (WHERE status = 'completed')::int,
+			COUNT(*) FILTER (WHERE status = 'cancelled')::int
+		FROM subtree
+		GROUP BY root_id`
+
+	rows, err := r.pool.Query(ctx, query, ids, tenantID, model.MaxTaskDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count subtasks: %w", err)
+	}
+	defer rows.Close()
+
+	progress := make(map[string]model.TaskProgress)
+	for rows.Next() {
+		var id string
+		var counts, p model.TaskProgress
+		if err := rows.Scan(&id, &counts.Subtasks, &counts.Completed, &counts.Cancelled); err != nil {
+			return nil, fmt.Errorf("failed to scan subtask counts: %w", err)
+		}
+		p.Merge(counts)
+		progress[id] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate subtask counts: %w", err)
+	}
+
+	return progress, nil
+}
+
+// missOrConflict explains why a conditional write matched no rows
+func missOrConflict(ctx context.Context, db dbtx, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND tenant_id = $2 AND deleted_at IS NULL)`
+	if err := db.QueryRow(ctx, query, id, tenantID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check task: %w", err)
+	}
+	if exists {
+		return ErrVersionConflict
+	}
+	return ErrTaskNotFound
 }
 
 // Ping checks the database connection
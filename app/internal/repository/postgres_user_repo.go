@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// PostgresUserRepository handles PostgreSQL operations for users. Every
+// query is limited to the tenant of its context.
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresUserRepository creates a new PostgreSQL user repository
+func NewPostgresUserRepository(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{pool: pool}
+}
+
+// userColumns is the column list matching scanUser
+const userColumns = `id, tenant_id, username, email, display_name, created_at, updated_at`
+
+func scanUser(row pgx.Row) (*model.User, error) {
+	var u model.User
+	if err := row.Scan(&u.ID, &u.TenantID, &u.Username, &u.Email, &u.DisplayName, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// Create inserts a new user into the context's tenant
+func (r *PostgresUserRepository) Create(ctx context.Context, req model.UserCreateRequest) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	query := `
+		INSERT INTO users (id, tenant_id, username, email, display_name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.pool.QueryRow(ctx, query,
+		uuid.New().String(), tenantID, req.Username, req.Email, req.DisplayName, now, now,
+	))
+	if isUniqueViolation(err) {
+		return nil, ErrUserExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := scanUser(r.pool.QueryRow(ctx,
+		`SELECT `+userColumns+` FROM users WHERE id = $1 AND tenant_id = $2`, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return user, nil
+}
+
+// List retrieves one page of the tenant's users ordered by username, with
+// the total count
+func (r *PostgresUserRepository) List(ctx context.Context, page, perPage int) ([]model.User, int, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM users WHERE tenant_id = $1`, tenantID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE tenant_id = $1 ORDER BY lower(username) LIMIT $2 OFFSET $3`
+	rows, err := r.pool.Query(ctx, query, tenantID, perPage, (page-1)*perPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+// Update modifies the fields set in req
+func (r *PostgresUserRepository) Update(ctx context.Context, id string, req model.UserUpdateRequest) (*model.User, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE users
+		SET username = COALESCE($1, username),
+			email = COALESCE($2, email),
+			display_name = COALESCE($3, display_name),
+			updated_at = NOW()
+		WHERE id = $4 AND tenant_id = $5
+		RETURNING ` + userColumns
+
+	user, err := scanUser(r.pool.QueryRow(ctx, query, req.Username, req.Email, req.DisplayName, id, tenantID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrUserExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return user, nil
+}
+
+// Delete removes a user; task references are cleared by ON DELETE SET NULL
+func (r *PostgresUserRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.pool.Exec(ctx, `DELETE FROM users WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
@@ -8,15 +8,34 @@ import (
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/hamfa/task-manager/internal/metrics"
 	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
 )
 
 const (
 	taskCachePrefix = "task:"
 	taskCacheTTL    = 5 * time.Minute
+	taskCacheName   = "task"
 )
 
-// RedisCache handles Redis caching operations
+const (
+	projectSummaryCachePrefix = "project_summary:"
+	projectSummaryCacheName   = "project_summary"
+)
+
+// projectSummaryCacheKey namespaces summary keys by tenant like taskCacheKey
+func projectSummaryCacheKey(tenantID, projectID string) string {
+	return projectSummaryCachePrefix + tenantID + ":" + projectID
+}
+
+// taskCacheKey namespaces cache keys by tenant, e.g. "task:acme:<id>"
+func taskCacheKey(tenantID, id string) string {
+	return taskCachePrefix + tenantID + ":" + id
+}
+
+// RedisCache handles Redis caching operations. Keys are scoped to the tenant
+// of the context, or of the task for SetTask.
 type RedisCache struct {
 	client *redis.Client
 }
@@ -28,10 +47,15 @@ func NewRedisCache(client *redis.Client) *RedisCache {
 
 // GetTask retrieves a cached task
 func (c *RedisCache) GetTask(ctx context.Context, id string) (*model.Task, error) {
-	key := taskCachePrefix + id
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := taskCacheKey(tenantID, id)
 
 	data, err := c.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
+		metrics.CacheMissesTotal.WithLabelValues(taskCacheName).Inc()
 		return nil, nil // Cache miss
 	}
 	if err != nil {
@@ -43,12 +67,13 @@ func (c *RedisCache) GetTask(ctx context.Context, id string) (*model.Task, error
 		return nil, fmt.Errorf("failed to unmarshal cached task: %w", err)
 	}
 
+	metrics.CacheHitsTotal.WithLabelValues(taskCacheName).Inc()
 	return &task, nil
 }
 
 // SetTask caches a task
 func (c *RedisCache) SetTask(ctx context.Context, task *model.Task) error {
-	key := taskCachePrefix + task.ID
+	key := taskCacheKey(task.TenantID, task.ID)
 
 	data, err := json.Marshal(task)
 	if err != nil {
@@ -60,13 +85,43 @@ func (c *RedisCache) SetTask(ctx context.Context, task *model.Task) error {
 
 // InvalidateTask removes a task from cache
 func (c *RedisCache) InvalidateTask(ctx context.Context, id string) error {
-	key := taskCachePrefix + id
-	return c.client.Del(ctx, key).Err()
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+	return c.client.Del(ctx, taskCacheKey(tenantID, id)).Err()
 }
 
-// InvalidateAll clears all task caches
+// InvalidateTasks removes several tasks from cache with a single DEL
+func (c *RedisCache) InvalidateTasks(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = taskCacheKey(tenantID, id)
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// InvalidateAll clears the cached tasks of the context's tenant, or of every
+// tenant for tenant.All
 func (c *RedisCache) InvalidateAll(ctx context.Context) error {
-	iter := c.client.Scan(ctx, 0, taskCachePrefix+"*", 100).Iterator()
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+	pattern := taskCacheKey(id, "*")
+	if id == tenant.All {
+		pattern = taskCachePrefix + "*"
+	}
+
+	iter := c.client.Scan(ctx, 0, pattern, 100).Iterator()
 	for iter.Next(ctx) {
 		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
 			return err
@@ -75,6 +130,63 @@ func (c *RedisCache) InvalidateAll(ctx context.Context) error {
 	return iter.Err()
 }
 
+// GetProjectSummary retrieves a cached project summary
+func (c *RedisCache) GetProjectSummary(ctx context.Context, projectID string) (*model.ProjectSummary, error) {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := c.client.Get(ctx, projectSummaryCacheKey(tenantID, projectID)).Bytes()
+	if err == redis.Nil {
+		metrics.CacheMissesTotal.WithLabelValues(projectSummaryCacheName).Inc()
+		return nil, nil // Cache miss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached project summary: %w", err)
+	}
+
+	var summary model.ProjectSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached project summary: %w", err)
+	}
+
+	metrics.CacheHitsTotal.WithLabelValues(projectSummaryCacheName).Inc()
+	return &summary, nil
+}
+
+// SetProjectSummary caches a project summary
+func (c *RedisCache) SetProjectSummary(ctx context.Context, summary *model.ProjectSummary) error {
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		return fmt.Errorf("failed to marshal project summary: %w", err)
+	}
+
+	return c.client.Set(ctx, projectSummaryCacheKey(tenantID, summary.ProjectID), data, taskCacheTTL).Err()
+}
+
+// InvalidateProjectSummaries removes several project summaries with a single DEL
+func (c *RedisCache) InvalidateProjectSummaries(ctx context.Context, projectIDs []string) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	tenantID, err := scopedTenant(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, len(projectIDs))
+	for i, id := range projectIDs {
+		keys[i] = projectSummaryCacheKey(tenantID, id)
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
 // Ping checks the Redis connection
 func (c *RedisCache) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// s3PartSize bounds the memory used to upload a blob of unknown size; the
+// default part size for unknown sizes is several hundred MiB
+const s3PartSize = 5 << 20
+
+// S3BlobStore keeps blobs as objects of one bucket in an S3-compatible
+// service such as MinIO
+type S3BlobStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3BlobStore creates a blob store over bucket
+func NewS3BlobStore(client *minio.Client, bucket string) *S3BlobStore {
+	return &S3BlobStore{client: client, bucket: bucket}
+}
+
+// EnsureBucket creates the bucket if it does not exist yet
+func (s *S3BlobStore) EnsureBucket(ctx context.Context, region string) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{Region: region}); err != nil {
+		return fmt.Errorf("failed to create bucket: %w", err)
+	}
+	return nil
+}
+
+// Put uploads the content; a failed multipart upload is aborted, so nothing
+// is left behind
+func (s *S3BlobStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	opts := minio.PutObjectOptions{ContentType: contentType, PartSize: s3PartSize}
+	if _, err := s.client.PutObject(ctx, s.bucket, key, r, -1, opts); err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return nil
+}
+
+// Get opens an object for reading
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+
+	// GetObject is lazy; Stat reports a missing object
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrBlobNotFound
+		}
+		return nil, fmt.Errorf("failed to get blob: %w", err)
+	}
+	return obj, nil
+}
+
+// Delete removes an object
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to delete blob: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix removes every object under prefix with multi-object deletes
+func (s *S3BlobStore) DeletePrefix(ctx context.Context, prefix string) error {
+	// Stops the listing goroutine when returning before it is drained
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var found []minio.ObjectInfo
+	for obj := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return fmt.Errorf("failed to list blobs: %w", obj.Err)
+		}
+		found = append(found, obj)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	objects := make(chan minio.ObjectInfo, len(found))
+	for _, obj := range found {
+		objects <- obj
+	}
+	close(objects)
+
+	var firstErr error
+	for e := range s.client.RemoveObjects(ctx, s.bucket, objects, minio.RemoveObjectsOptions{}) {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to delete blob %s: %w", e.ObjectName, e.Err)
+		}
+	}
+	return firstErr
+}
+
+// Ping checks that the bucket is reachable
+func (s *S3BlobStore) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucket)
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// TaskStore persists tasks. A parent_id set by Create, Update or Modify must
+// name another task of the tenant that is not one of the task's subtasks and
+// has fewer than model.MaxTaskDepth ancestors; otherwise the write fails with
+// ErrInvalidParent. Tasks in the trash are invisible to every method except
+// Restore, Purge, PurgeTrash and List with Filter.Trashed set.
+type TaskStore interface {
+	Create(ctx context.Context, req model.TaskCreateRequest) (*model.Task, error)
+	GetByID(ctx context.Context, id string) (*model.Task, error)
+	List(ctx context.Context, q model.TaskListQuery) (*model.TaskPage, error)
+	// Update and Delete apply only if the stored version equals expectedVersion;
+	// pass 0 to skip the check. A mismatch returns ErrVersionConflict.
+	Update(ctx context.Context, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error)
+	// Delete moves a task to the trash, handles its subtasks according to
+	// onSubtasks (model.SubtasksReject returns ErrHasSubtasks) and returns the
+	// subtasks it trashed or detached
+	Delete(ctx context.Context, id string, expectedVersion int, onSubtasks string) ([]model.Task, error)
+	// Restore takes a trashed task out of the trash with the subtasks trashed
+	// along with it. A parent still in the trash returns ErrParentInTrash.
+	Restore(ctx context.Context, id string) (*model.Task, []model.Task, error)
+	// Purge permanently deletes a trashed task and its trashed subtasks
+	Purge(ctx context.Context, id string) (*model.Task, []model.Task, error)
+	// PurgeTrash permanently deletes up to limit tasks of every tenant that
+	// were trashed before cutoff, oldest first
+	PurgeTrash(ctx context.Context, cutoff time.Time, limit int) ([]model.Task, error)
+	// Modify atomically reads a task, lets fn change it and writes it back.
+	// An error from fn aborts the write and is returned unchanged.
+	Modify(ctx context.Context, id string, expectedVersion int, fn func(*model.Task) error) (*model.Task, error)
+	// Batch applies ops in order with one result per op. In atomic mode the
+	// first failure rolls back every op; the error return is reserved for
+	// failures of the batch itself.
+	Batch(ctx context.Context, ops []TaskBatchOp, atomic bool) ([]TaskBatchResult, error)
+	// ClaimReminders passes up to limit open tasks that are due for the given
+	// reminder kind at now (model.ReminderDueSoon: due within window;
+	// model.ReminderOverdue: past due) and not yet reminded for their current
+	// due date to fn. The tasks are marked reminded only if fn succeeds.
+	ClaimReminders(ctx context.Context, kind string, now time.Time, window time.Duration, limit int, fn func([]model.Task) error) error
+	// ReleaseUser clears every assignee and reporter reference to userID in
+	// the tenant
+	ReleaseUser(ctx context.Context, userID string) ([]ReleasedTask, error)
+	// TagCounts returns the number of tasks per tag, most used first
+	TagCounts(ctx context.Context) ([]model.TagCount, error)
+	// Subtree returns a task followed by its subtasks at every depth, level
+	// by level
+	Subtree(ctx context.Context, id string) ([]model.Task, error)
+	// SubtaskProgress rolls up the subtasks of each of ids; tasks without
+	// subtasks are left out of the result
+	SubtaskProgress(ctx context.Context, ids []string) (map[string]model.TaskProgress, error)
+	Ping(ctx context.Context) error
+}
+
+// ReleasedTask is a task updated by TaskStore.ReleaseUser. WasAssignee is
+// true if the released user was its assignee (rather than only its reporter).
+type ReleasedTask struct {
+	Task        model.Task
+	WasAssignee bool
+}
+
+// UserStore persists users of the context's tenant. Usernames and emails are
+// unique per tenant, ignoring case; a duplicate returns ErrUserExists.
+type UserStore interface {
+	Create(ctx context.Context, req model.UserCreateRequest) (*model.User, error)
+	GetByID(ctx context.Context, id string) (*model.User, error)
+	List(ctx context.Context, page, perPage int) ([]model.User, int, error)
+	Update(ctx context.Context, id string, req model.UserUpdateRequest) (*model.User, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ProjectStore persists projects of the context's tenant. Project names are
+// unique per tenant, ignoring case; a duplicate returns ErrProjectExists.
+type ProjectStore interface {
+	Create(ctx context.Context, req model.ProjectCreateRequest) (*model.Project, error)
+	GetByID(ctx context.Context, id string) (*model.Project, error)
+	// List omits archived projects unless includeArchived is set
+	List(ctx context.Context, page, perPage int, includeArchived bool) ([]model.Project, int, error)
+	Update(ctx context.Context, id string, req model.ProjectUpdateRequest) (*model.Project, error)
+	// Delete returns ErrProjectNotEmpty while tasks belong to the project,
+	// including tasks in the trash
+	Delete(ctx context.Context, id string) error
+	// Summary counts the project's tasks per status and priority
+	Summary(ctx context.Context, id string) (*model.ProjectSummary, error)
+	// ReleaseOwner clears every owner reference to userID in the tenant
+	ReleaseOwner(ctx context.Context, userID string) error
+}
+
+// DependencyStore persists the blocked-by edges between tasks of a tenant
+type DependencyStore interface {
+	// Add records that taskID is blocked by blockerID. It returns
+	// ErrTaskNotFound for an unknown task, ErrInvalidDependency for an unknown
+	// blocker or the task itself, ErrDependencyExists for a duplicate and
+	// ErrDependencyCycle if blockerID already waits on taskID.
+	Add(ctx context.Context, taskID, blockerID string) (*model.TaskDependency, error)
+	// Remove returns ErrDependencyNotFound if the edge does not exist
+	Remove(ctx context.Context, taskID, blockerID string) error
+	// Blockers returns the tasks blocking taskID, oldest first
+	Blockers(ctx context.Context, taskID string) ([]model.Task, error)
+	// Edges returns the dependencies between tasks that are all in ids
+	Edges(ctx context.Context, ids []string) ([]model.TaskDependency, error)
+}
+
+// APIKeyStore persists API keys together with the hash of their secret
+type APIKeyStore interface {
+	Create(ctx context.Context, key model.APIKey, hash string) (*model.APIKey, error)
+	// GetByPrefix returns the key and its secret hash
+	GetByPrefix(ctx context.Context, prefix string) (*model.APIKey, string, error)
+	List(ctx context.Context) ([]model.APIKey, error)
+	Revoke(ctx context.Context, id string, at time.Time) (*model.APIKey, error)
+	// TouchLastUsed records a use at most once per minute per key
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
+
+// ActivityStore records and queries task activity logs
+type ActivityStore interface {
+	LogActivity(ctx context.Context, taskID, action, details string) error
+	LogActivities(ctx context.Context, logs []model.ActivityLog) error
+	GetActivities(ctx context.Context, taskID string, limit int64) ([]model.ActivityLog, error)
+	// GetActivitiesByAction retrieves the activity logs of a task whose
+	// action is one of actions, newest first
+	GetActivitiesByAction(ctx context.Context, taskID string, actions []string, limit int64) ([]model.ActivityLog, error)
+	GetRecentActivities(ctx context.Context, limit int64) ([]model.ActivityLog, error)
+	Ping(ctx context.Context) error
+}
+
+// CommentStore persists the comments of the context's tenant. Creating a
+// reply increments its parent's reply_count; deleting one decrements it.
+type CommentStore interface {
+	// CreateComment stores a new comment. A reply to a reply is attached to the
+	// thread's top-level comment; an unknown or deleted parent returns
+	// ErrInvalidCommentParent.
+	CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error)
+	GetComment(ctx context.Context, taskID, id string) (*model.Comment, error)
+	// ListComments returns comments oldest first
+	ListComments(ctx context.Context, q model.CommentListQuery) (*model.CommentPage, error)
+	// UpdateComment replaces the body, moving the previous one into the
+	// history. Deleted comments return ErrCommentNotFound.
+	UpdateComment(ctx context.Context, taskID, id, body string) (*model.Comment, error)
+	// DeleteComment removes a comment. A top-level comment with replies is only
+	// marked deleted, and is removed with its last reply.
+	DeleteComment(ctx context.Context, taskID, id string) error
+}
+
+// AttachmentStore persists attachment metadata of the context's tenant
+type AttachmentStore interface {
+	// Create returns ErrTaskNotFound if the task does not exist
+	Create(ctx context.Context, attachment model.Attachment) (*model.Attachment, error)
+	GetByID(ctx context.Context, taskID, id string) (*model.Attachment, error)
+	// List returns the attachments of a task, oldest first
+	List(ctx context.Context, taskID string) ([]model.Attachment, error)
+	// Delete returns the removed attachment, whose content is left to the caller
+	Delete(ctx context.Context, taskID, id string) (*model.Attachment, error)
+}
+
+// BlobStore keeps attachment contents under slash-separated keys, see
+// AttachmentKey
+type BlobStore interface {
+	// Put stores everything read from r under key. When reading fails nothing
+	// is stored.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get returns ErrBlobNotFound for an unknown key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes a blob; an unknown key is not an error
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every blob under prefix, which ends with a slash
+	DeletePrefix(ctx context.Context, prefix string) error
+	Ping(ctx context.Context) error
+}
+
+// TaskCache caches individual tasks. GetTask returns nil, nil on a miss.
+type TaskCache interface {
+	GetTask(ctx context.Context, id string) (*model.Task, error)
+	SetTask(ctx context.Context, task *model.Task) error
+	InvalidateTask(ctx context.Context, id string) error
+	InvalidateTasks(ctx context.Context, ids []string) error
+	InvalidateAll(ctx context.Context) error
+	Ping(ctx context.Context) error
+}
+
+// ProjectSummaryCache caches project summaries of the context's tenant.
+// GetProjectSummary returns nil, nil on a miss.
+type ProjectSummaryCache interface {
+	GetProjectSummary(ctx context.Context, projectID string) (*model.ProjectSummary, error)
+	SetProjectSummary(ctx context.Context, summary *model.ProjectSummary) error
+	InvalidateProjectSummaries(ctx context.Context, projectIDs []string) error
+}
+
+// Compile-time interface checks
+var (
+	_ TaskStore     = (*PostgresRepository)(nil)
+	_ TaskStore     = (*MemoryTaskStore)(nil)
+	_ UserStore     = (*PostgresUserRepository)(nil)
+	_ UserStore     = (*MemoryUserStore)(nil)
+	_ ProjectStore  = (*PostgresProjectRepository)(nil)
+	_ ProjectStore  = (*MemoryProjectStore)(nil)
+	_ APIKeyStore   = (*PostgresAPIKeyRepository)(nil)
+	_ APIKeyStore   = (*MemoryAPIKeyStore)(nil)
+	_ ActivityStore = (*MongoRepository)(nil)
+	_ ActivityStore = (*MemoryActivityStore)(nil)
+	_ CommentStore  = (*MongoRepository)(nil)
+	_ CommentStore  = (*MemoryCommentStore)(nil)
+	_ TaskCache     = (*RedisCache)(nil)
+	_ TaskCache     = (*MemoryCache)(nil)
+
+	_ ProjectSummaryCache = (*RedisCache)(nil)
+	_ ProjectSummaryCache = (*MemoryCache)(nil)
+
+	_ AttachmentStore = (*PostgresAttachmentRepository)(nil)
+	_ AttachmentStore = (*MemoryAttachmentStore)(nil)
+	_ BlobStore       = (*LocalBlobStore)(nil)
+	_ BlobStore       = (*S3BlobStore)(nil)
+)
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// scopedTenant returns the tenant of a tenant-scoped operation. Contexts
+// without a tenant, or scoped to tenant.All, are rejected with ErrNoTenant.
+func scopedTenant(ctx context.Context) (string, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok || id == tenant.All {
+		return "", ErrNoTenant
+	}
+	return id, nil
+}
+
+// tenantFilter returns the tenant an operation on shared records is limited
+// to, or "" for tenant.All
+func tenantFilter(ctx context.Context) (string, error) {
+	id, ok := tenant.FromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	if id == tenant.All {
+		return "", nil
+	}
+	return id, nil
+}
+
+// SetTenantSetting is a pgxpool BeforeAcquire hook that copies the context's
+// tenant into the app.tenant_id setting read by the row-level security
+// policies. Connections acquired without a tenant see no rows.
+func SetTenantSetting(ctx context.Context, conn *pgx.Conn) bool {
+	id, _ := tenant.FromContext(ctx)
+	_, err := conn.Exec(ctx, `SELECT set_config('app.tenant_id', $1, false)`, id)
+	return err == nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+func TestAPIKeyTenantBinding(t *testing.T) {
+	s := NewAPIKeyService(repository.NewMemoryAPIKeyStore(), zap.NewNop())
+	req := model.APIKeyCreateRequest{Name: "ci", Scopes: []string{model.ScopeTasksRead}}
+
+	tests := []struct {
+		name   string
+		tenant string
+		want   string
+	}{
+		{"bound to the creator's tenant", "acme", "acme"},
+		{"unbound when created across tenants", tenant.All, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext(tt.tenant)
+			key, raw, err := s.Create(ctx, req)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if key.TenantID != tt.want || !strings.HasPrefix(raw, key.Prefix+"_") {
+				t.Fatalf("Create() = %+v, %q; want tenant %q and the key to start with its prefix", key, raw, tt.want)
+			}
+
+			p, err := s.Authenticate(ctx, raw)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if p.Tenant != tt.want || p.Method != auth.MethodAPIKey || len(p.Scopes) != 1 || p.Scopes[0] != model.ScopeTasksRead {
+				t.Errorf("Authenticate() = %+v, want tenant %q with the key's scopes", p, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown scope", func(t *testing.T) {
+		_, _, err := s.Create(testContext("acme"), model.APIKeyCreateRequest{Name: "ci", Scopes: []string{"tasks:delete"}})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Create() error = %v, want ErrValidation", err)
+		}
+	})
+}
+
+func TestAPIKeyRevoke(t *testing.T) {
+	s := NewAPIKeyService(repository.NewMemoryAPIKeyStore(), zap.NewNop())
+	acme, globex := testContext("acme"), testContext("globex")
+	key, raw, err := s.Create(acme, model.APIKeyCreateRequest{Name: "ci", Scopes: []string{model.ScopeTasksWrite}})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := s.Revoke(globex, key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("Revoke() from another tenant error = %v, want ErrAPIKeyNotFound", err)
+	}
+	if keys, _ := s.List(globex); len(keys) != 0 {
+		t.Fatalf("List() in another tenant = %+v, want no keys", keys)
+	}
+	if _, err := s.Authenticate(acme, raw); err != nil {
+		t.Fatalf("Authenticate() error = %v after a refused revoke", err)
+	}
+
+	revoked, err := s.Revoke(acme, key.ID)
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked.RevokedAt == nil {
+		t.Fatalf("Revoke() = %+v, want revoked_at set", revoked)
+	}
+	if _, err := s.Authenticate(acme, raw); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Errorf("Authenticate() with a revoked key error = %v, want ErrUnauthenticated", err)
+	}
+
+	t.Run("wrong secret", func(t *testing.T) {
+		_, raw, err := s.Create(acme, model.APIKeyCreateRequest{Name: "other", Scopes: []string{model.ScopeTasksRead}})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		forged := raw[:strings.LastIndexByte(raw, '_')+1] + strings.Repeat("0", 2*apiKeySecretBytes)
+		if _, err := s.Authenticate(acme, forged); !errors.Is(err, auth.ErrUnauthenticated) {
+			t.Errorf("Authenticate() with a wrong secret error = %v, want ErrUnauthenticated", err)
+		}
+	})
+}
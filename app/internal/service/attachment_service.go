@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// sniffLength is how much of an upload content type detection looks at
+const sniffLength = 512
+
+// AttachmentService handles business logic for task attachments
+type AttachmentService struct {
+	attachmentStore repository.AttachmentStore
+	blobStore       repository.BlobStore
+	tasks           *TaskService
+	maxSize         int64
+	logger          *zap.Logger
+}
+
+// NewAttachmentService creates a new attachment service accepting files of
+// up to maxSize bytes
+func NewAttachmentService(
+	attachments repository.AttachmentStore,
+	blobs repository.BlobStore,
+	tasks *TaskService,
+	maxSize int64,
+	logger *zap.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentStore: attachments,
+		blobStore:       blobs,
+		tasks:           tasks,
+		maxSize:         maxSize,
+		logger:          logger,
+	}
+}
+
+// MaxSize returns the largest accepted attachment in bytes
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Upload is a file to attach to a task
+type Upload struct {
+	Filename string
+	Content  io.Reader
+	// Checksum is the hex SHA-256 the client expects; empty skips the check
+	Checksum string
+}
+
+// Upload stores the content of a file and attaches it to a task. The
+// content type is detected from the content, not taken from the client.
+func (s *AttachmentService) Upload(ctx context.Context, taskID string, up Upload) (*model.Attachment, error) {
+	filename := strings.TrimSpace(up.Filename)
+	if filename == "" || len(filename) > 255 {
+		return nil, fmt.Errorf("%w: file: filename must be 1 to 255 bytes", ErrValidation)
+	}
+	if up.Checksum != "" && !isSHA256Hex(up.Checksum) {
+		return nil, fmt.Errorf("%w: sha256: must be 64 hex characters", ErrValidation)
+	}
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(up.Content, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("service: read attachment: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: file: must not be empty", ErrValidation)
+	}
+	head = head[:n]
+
+	tenantID, _ := tenant.FromContext(ctx)
+	a := model.Attachment{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		Filename:    filename,
+		ContentType: http.DetectContentType(head),
+		UploadedBy:  auth.Actor(ctx),
+	}
+	a.StorageKey = repository.AttachmentKey(tenantID, taskID, a.ID)
+
+	content := &countingReader{
+		r:     io.MultiReader(bytes.NewReader(head), up.Content),
+		hash:  sha256.New(),
+		limit: s.maxSize,
+	}
+	if err := s.blobStore.Put(ctx, a.StorageKey, content, a.ContentType); err != nil {
+		s.removeBlob(ctx, a.StorageKey)
+		if errors.Is(content.err, ErrAttachmentTooLarge) {
+			return nil, content.err
+		}
+		if content.err != nil {
+			return nil, fmt.Errorf("service: read attachment: %w", content.err)
+		}
+		return nil, fmt.Errorf("service: store attachment: %w", err)
+	}
+	a.Size = content.n
+	a.Checksum = hex.EncodeToString(content.hash.Sum(nil))
+
+	if up.Checksum != "" && !strings.EqualFold(up.Checksum, a.Checksum) {
+		s.removeBlob(ctx, a.StorageKey)
+		return nil, &ChecksumError{Expected: strings.ToLower(up.Checksum), Actual: a.Checksum}
+	}
+
+	attachment, err := s.attachmentStore.Create(ctx, a)
+	if err != nil {
+		// The task may have been deleted during the upload
+		s.removeBlob(ctx, a.StorageKey)
+		return nil, fmt.Errorf("service: create attachment: %w", err)
+	}
+
+	s.tasks.logActivities(ctx, []model.ActivityLog{{TaskID: taskID, Action: "attachment_added",
+		Details: fmt.Sprintf("File '%s' attached (%d bytes)", a.Filename, a.Size), To: a.ID}})
+
+	return attachment, nil
+}
+
+// GetByID retrieves the metadata of an attachment
+func (s *AttachmentService) GetByID(ctx context.Context, taskID, id string) (*model.Attachment, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentStore.GetByID(ctx, taskID, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+// List returns the attachments of a task, oldest first
+func (s *AttachmentService) List(ctx context.Context, taskID string) ([]model.Attachment, error) {
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentStore.List(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("service: list attachments: %w", err)
+	}
+	if attachments == nil {
+		attachments = []model.Attachment{}
+	}
+	return attachments, nil
+}
+
+// Open returns a reader for the content of an attachment. The reader fails
+// at the end instead of returning io.EOF if the content no longer matches
+// the stored checksum.
+func (s *AttachmentService) Open(ctx context.Context, attachment *model.Attachment) (io.ReadCloser, error) {
+	blob, err := s.blobStore.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("service: open attachment: %w", err)
+	}
+
+	return &verifyingReader{
+		ReadCloser: blob,
+		hash:       sha256.New(),
+		want:       attachment.Checksum,
+		onMismatch: func() {
+			s.logger.Error("attachment content does not match its checksum",
+				zap.String("attachment_id", attachment.ID), zap.String("key", attachment.StorageKey))
+		},
+	}, nil
+}
+
+// Delete removes an attachment and its content
+func (s *AttachmentService) Delete(ctx context.Context, taskID, id string) error {
+	attachment, err := s.attachmentStore.Delete(ctx, taskID, id)
+	if err != nil {
+		return fmt.Errorf("service: delete attachment: %w", err)
+	}
+	s.removeBlob(ctx, attachment.StorageKey)
+
+	s.tasks.logActivities(ctx, []model.ActivityLog{{TaskID: taskID, Action: "attachment_removed",
+		Details: fmt.Sprintf("File '%s' removed", attachment.Filename), From: attachment.ID}})
+
+	return nil
+}
+
+// removeBlob deletes a blob, logging failures; an orphaned blob only wastes space
+func (s *AttachmentService) removeBlob(ctx context.Context, key string) {
+	if err := s.blobStore.Delete(ctx, key); err != nil {
+		s.logger.Warn("failed to delete attachment content", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// isSHA256Hex reports whether s is a hex-encoded SHA-256 digest
+func isSHA256Hex(s string) bool {
+	b, err := hex.DecodeString(s)
+	return err == nil && len(b) == sha256.Size
+}
+
+// countingReader hashes and counts what is read through it and fails with
+// an ErrAttachmentTooLarge error once more than limit bytes have been read. err keeps
+// the first read failure other than io.EOF.
+type countingReader struct {
+	r     io.Reader
+	hash  hash.Hash
+	n     int64
+	limit int64
+	err   error
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.hash.Write(p[:n])
+	if c.n > c.limit {
+		c.err = fmt.Errorf("%w: files are limited to %d bytes", ErrAttachmentTooLarge, c.limit)
+		return n, c.err
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+	return n, err
+}
+
+// verifyingReader hashes a blob while it is read and replaces the final
+// io.EOF with ErrChecksumMismatch when the content changed
+type verifyingReader struct {
+	io.ReadCloser
+	hash       hash.Hash
+	want       string
+	onMismatch func()
+	mismatch   bool
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	if v.mismatch {
+		return 0, ErrChecksumMismatch
+	}
+	n, err := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(v.hash.Sum(nil)) != v.want {
+		v.mismatch = true
+		v.onMismatch()
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// testMaxAttachmentSize keeps the size limit small enough to cross in a test
+const testMaxAttachmentSize = 16
+
+// newTestAttachmentService wires an AttachmentService to a TaskService from
+// newTestTaskService and a blob store in dir
+func newTestAttachmentService(t *testing.T, dir string) (*AttachmentService, *TaskService) {
+	t.Helper()
+
+	tasks := newTestTaskService(t)
+	blobs, err := repository.NewLocalBlobStore(dir)
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore() error = %v", err)
+	}
+	attachments := repository.NewMemoryAttachmentStore(tasks.taskStore.(*repository.MemoryTaskStore))
+	return NewAttachmentService(attachments, blobs, tasks, testMaxAttachmentSize, zap.NewNop()), tasks
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUploadRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		checksum string
+		err      error
+	}{
+		{"over the size limit", strings.Repeat("x", testMaxAttachmentSize+1), "", ErrAttachmentTooLarge},
+		{"checksum mismatch", "runbook", sha256Hex("other"), ErrChecksumMismatch},
+		{"malformed checksum", "runbook", "abc", ErrValidation},
+		{"empty file", "", "", ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s, tasks := newTestAttachmentService(t, dir)
+			ctx := testContext(tenant.Default)
+			task := createTask(t, ctx, tasks, "incident")
+
+			up := Upload{Filename: "runbook.txt", Content: strings.NewReader(tt.content), Checksum: tt.checksum}
+			if _, err := s.Upload(ctx, task.ID, up); !errors.Is(err, tt.err) {
+				t.Fatalf("Upload() error = %v, want %v", err, tt.err)
+			}
+
+			if list, _ := s.List(ctx, task.ID); len(list) != 0 {
+				t.Errorf("List() = %+v after a rejected upload, want none", list)
+			}
+			// A rejected upload must not leave its content behind
+			_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					t.Errorf("blob %s left behind", path)
+				}
+				return err
+			})
+		})
+	}
+}
+
+func TestUploadChecksums(t *testing.T) {
+	s, tasks := newTestAttachmentService(t, t.TempDir())
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, tasks, "incident")
+	content := strings.Repeat("y", testMaxAttachmentSize)
+
+	// Exactly at the limit, with the checksum in upper case
+	a, err := s.Upload(ctx, task.ID, Upload{Filename: "log.txt", Content: strings.NewReader(content),
+		Checksum: strings.ToUpper(sha256Hex(content))})
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if a.Size != testMaxAttachmentSize || a.Checksum != sha256Hex(content) || a.ContentType != "text/plain; charset=utf-8" {
+		t.Fatalf("Upload() = %+v, want its size, checksum and sniffed content type", a)
+	}
+
+	read := func() (string, error) {
+		r, err := s.Open(ctx, a)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		return string(b), err
+	}
+	if got, err := read(); err != nil || got != content {
+		t.Fatalf("reading the attachment = %q, %v; want %q", got, err, content)
+	}
+
+	// Content changed behind the service's back fails the read at the end
+	if err := s.blobStore.Put(context.Background(), a.StorageKey, strings.NewReader("tampered"), a.ContentType); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := read(); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("reading a tampered attachment error = %v, want ErrChecksumMismatch", err)
+	}
+}
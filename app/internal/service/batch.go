@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// BatchOutcome is the result of one batch operation
+type BatchOutcome struct {
+	Op   string
+	ID   string
+	Task *model.Task
+	Err  error
+}
+
+// Batch applies a list of create, update and delete operations. Invalid
+// operations fail individually, or abort everything in atomic mode. Cache
+// invalidation and activity logging are done once for the whole batch.
+func (s *TaskService) Batch(ctx context.Context, req model.BatchRequest) ([]BatchOutcome, error) {
+	outcomes := make([]BatchOutcome, len(req.Operations))
+	ops := make([]repository.TaskBatchOp, 0, len(req.Operations))
+	opIndex := make([]int, 0, len(req.Operations))   // store op → outcome index
+	prevs := make([]model.Task, len(req.Operations)) // task before each update
+
+	for i, op := range req.Operations {
+		outcomes[i] = BatchOutcome{Op: op.Op, ID: op.ID}
+
+		storeOp, err := s.prepareBatchOp(ctx, op)
+		if err == nil && storeOp.Kind == model.BatchOpUpdate {
+			// Subtasks and blockers are checked as they were before the batch
+			var open openWork
+			open, err = s.countOpenWork(ctx, storeOp.ID, storeOp.Update.Status)
+			update := storeOp.Update
+			storeOp.Modify = s.guardUpdate(ctx, &prevs[i], open, func(t *model.Task) error {
+				update.ApplyTo(t)
+				return nil
+			})
+		}
+		if err != nil {
+			if req.Atomic {
+				return abortedOutcomes(outcomes, req.Operations, i, err), nil
+			}
+			outcomes[i].Err = err
+			continue
+		}
+		ops = append(ops, storeOp)
+		opIndex = append(opIndex, i)
+	}
+
+	results, err := s.taskStore.Batch(ctx, ops, req.Atomic)
+	if err != nil {
+		return nil, fmt.Errorf("service: batch tasks: %w", err)
+	}
+
+	var touched []string
+	var changed []*model.Task // every version whose project summary is stale
+	var logs []model.ActivityLog
+	for j, res := range results {
+		o := &outcomes[opIndex[j]]
+		o.Task, o.Err = res.Task, res.Err
+		if res.Err != nil {
+			continue
+		}
+
+		o.ID = res.Task.ID
+		touched = append(touched, res.Task.ID)
+		changed = append(changed, res.Task, &prevs[opIndex[j]])
+		logs = append(logs, batchActivities(o.Op, res.Task, &prevs[opIndex[j]])...)
+
+		for k := range res.Subtasks {
+			touched = append(touched, res.Subtasks[k].ID)
+			changed = append(changed, &res.Subtasks[k])
+		}
+		logs = append(logs, subtaskActivities(res.Task, res.Subtasks, s.subtasks.OnDelete)...)
+	}
+
+	// Invalidate every touched task in one round trip
+	if cacheErr := s.taskCache.InvalidateTasks(ctx, touched); cacheErr != nil {
+		s.logger.Warn("failed to invalidate cache", zap.Error(cacheErr))
+	}
+	s.invalidateSummaries(ctx, changed...)
+
+	// Record all activities in one write; updates must not lag, see recordActivities
+	s.recordActivities(ctx, logs)
+
+	return outcomes, nil
+}
+
+// prepareBatchOp decodes and validates a batch operation into a store op
+func (s *TaskService) prepareBatchOp(ctx context.Context, op model.BatchOperation) (repository.TaskBatchOp, error) {
+	storeOp := repository.TaskBatchOp{Kind: op.Op, ID: op.ID, ExpectedVersion: op.Version, OnSubtasks: s.subtasks.OnDelete}
+
+	switch op.Op {
+	case model.BatchOpCreate:
+		if err := decodeBatchTask(op.Task, &storeOp.Create); err != nil {
+			return storeOp, err
+		}
+		if err := s.checkCreate(ctx, &storeOp.Create); err != nil {
+			return storeOp, err
+		}
+	case model.BatchOpUpdate:
+		if op.ID == "" {
+			return storeOp, fmt.Errorf("%w: id is required for update", ErrValidation)
+		}
+		if err := decodeBatchTask(op.Task, &storeOp.Update); err != nil {
+			return storeOp, err
+		}
+	case model.BatchOpDelete:
+		if op.ID == "" {
+			return storeOp, fmt.Errorf("%w: id is required for delete", ErrValidation)
+		}
+	default:
+		return storeOp, fmt.Errorf("%w: op must be create, update or delete", ErrValidation)
+	}
+
+	return storeOp, nil
+}
+
+// decodeBatchTask unmarshals and validates the task payload of an operation
+func decodeBatchTask(raw json.RawMessage, dst interface{}) error {
+	if len(raw) == 0 {
+		return fmt.Errorf("%w: task is required", ErrValidation)
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	if err := binding.Validator.ValidateStruct(dst); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+	return nil
+}
+
+// abortedOutcomes fails the operation at index with err and marks the rest aborted
+func abortedOutcomes(outcomes []BatchOutcome, ops []model.BatchOperation, failed int, err error) []BatchOutcome {
+	for i, op := range ops {
+		outcomes[i] = BatchOutcome{Op: op.Op, ID: op.ID, Err: ErrBatchAborted}
+	}
+	outcomes[failed].Err = err
+	return outcomes
+}
+
+// batchActivities builds the activity entries for a successful batch operation
+func batchActivities(op string, task, prev *model.Task) []model.ActivityLog {
+	switch op {
+	case model.BatchOpCreate:
+		return []model.ActivityLog{{TaskID: task.ID, Action: "created", Version: task.Version,
+			Details: fmt.Sprintf("Task '%s' created with priority %s", task.Title, task.Priority)}}
+	case model.BatchOpUpdate:
+		return updateActivities(task, prev, fmt.Sprintf("Task '%s' updated", task.Title))
+	case model.BatchOpDelete:
+		return []model.ActivityLog{{TaskID: task.ID, Action: "deleted",
+			Details: fmt.Sprintf("Task '%s' moved to trash", task.Title)}}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+func TestBatchPartialFailure(t *testing.T) {
+	raw := func(v interface{}) json.RawMessage {
+		b, _ := json.Marshal(v)
+		return b
+	}
+	title := "renamed"
+	missing := "00000000-0000-0000-0000-000000000000"
+
+	tests := []struct {
+		name   string
+		atomic bool
+		// ops gets the ID and current version of an existing task
+		ops  func(id string, version int) []model.BatchOperation
+		errs []error // per operation; nil means it applied
+	}{
+		{
+			name: "independent failures",
+			ops: func(id string, version int) []model.BatchOperation {
+				return []model.BatchOperation{
+					{Op: model.BatchOpCreate, Task: raw(model.TaskCreateRequest{Title: "new"})},
+					{Op: model.BatchOpUpdate, ID: id, Version: version - 1, Task: raw(model.TaskUpdateRequest{Title: &title})},
+					{Op: model.BatchOpDelete, ID: missing},
+					{Op: model.BatchOpCreate, Task: raw(map[string]string{"title": ""})},
+					{Op: model.BatchOpUpdate, ID: id, Version: version, Task: raw(model.TaskUpdateRequest{Title: &title})},
+				}
+			},
+			errs: []error{nil, ErrVersionConflict, ErrTaskNotFound, ErrValidation, nil},
+		},
+		{
+			name:   "atomic, invalid operation",
+			atomic: true,
+			ops: func(id string, version int) []model.BatchOperation {
+				return []model.BatchOperation{
+					{Op: model.BatchOpCreate, Task: raw(model.TaskCreateRequest{Title: "new"})},
+					{Op: "archive", ID: id},
+					{Op: model.BatchOpDelete, ID: id},
+				}
+			},
+			errs: []error{ErrBatchAborted, ErrValidation, ErrBatchAborted},
+		},
+		{
+			name:   "atomic, failure in the store",
+			atomic: true,
+			ops: func(id string, version int) []model.BatchOperation {
+				return []model.BatchOperation{
+					{Op: model.BatchOpCreate, Task: raw(model.TaskCreateRequest{Title: "new"})},
+					{Op: model.BatchOpUpdate, ID: id, Version: version, Task: raw(model.TaskUpdateRequest{Title: &title})},
+					{Op: model.BatchOpDelete, ID: missing},
+				}
+			},
+			errs: []error{ErrBatchAborted, ErrBatchAborted, ErrTaskNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTaskService(t)
+			ctx := testContext(tenant.Default)
+			task := createTask(t, ctx, s, "existing")
+			// Version 1 is now stale
+			task, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Title: &task.Title}, 0)
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			outcomes, err := s.Batch(ctx, model.BatchRequest{Atomic: tt.atomic, Operations: tt.ops(task.ID, task.Version)})
+			if err != nil {
+				t.Fatalf("Batch() error = %v", err)
+			}
+			if len(outcomes) != len(tt.errs) {
+				t.Fatalf("Batch() returned %d outcomes, want %d", len(outcomes), len(tt.errs))
+			}
+			applied := 0
+			for i, o := range outcomes {
+				if tt.errs[i] == nil {
+					if o.Err != nil || o.Task == nil {
+						t.Errorf("operation %d: error = %v, want it applied", i, o.Err)
+					}
+					applied++
+					continue
+				}
+				if !errors.Is(o.Err, tt.errs[i]) {
+					t.Errorf("operation %d: error = %v, want %v", i, o.Err, tt.errs[i])
+				}
+			}
+
+			resp, err := s.List(ctx, model.TaskListQuery{})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			got, err := s.GetByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if applied == 0 && (len(resp.Data) != 1 || got.Version != task.Version) {
+				t.Errorf("failed atomic batch left %d tasks, version %d; want it to change nothing", len(resp.Data), got.Version)
+			}
+			if applied > 0 && (len(resp.Data) != 2 || got.Title != title) {
+				t.Errorf("batch left %d tasks titled %q, want the create and the current update applied", len(resp.Data), got.Title)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// CommentService handles business logic for task comments
+type CommentService struct {
+	commentStore repository.CommentStore
+	tasks        *TaskService
+	logger       *zap.Logger
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(comments repository.CommentStore, tasks *TaskService, logger *zap.Logger) *CommentService {
+	return &CommentService{
+		commentStore: comments,
+		tasks:        tasks,
+		logger:       logger,
+	}
+}
+
+// Create adds a comment to a task, or a reply when req.ParentID is set, and
+// records it in the task's activity log
+func (s *CommentService) Create(ctx context.Context, taskID string, req model.CommentCreateRequest) (*model.Comment, error) {
+	body, err := commentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentStore.CreateComment(ctx, model.Comment{
+		TaskID:   taskID,
+		ParentID: req.ParentID,
+		Author:   auth.Actor(ctx),
+		Body:     body,
+	})
+	if errors.Is(err, ErrInvalidCommentParent) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: create comment: %w", err)
+	}
+
+	details := "Comment added"
+	if comment.ParentID != "" {
+		details = fmt.Sprintf("Reply added to comment %s", comment.ParentID)
+	}
+	s.tasks.logActivities(ctx, []model.ActivityLog{{TaskID: taskID, Action: "commented",
+		Details: details, CommentID: comment.ID}})
+
+	return comment, nil
+}
+
+// GetByID retrieves a comment of a task
+func (s *CommentService) GetByID(ctx context.Context, taskID, id string) (*model.Comment, error) {
+	comment, err := s.commentStore.GetComment(ctx, taskID, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get comment: %w", err)
+	}
+	return comment, nil
+}
+
+// List retrieves one page of the top-level comments of a task, or of the
+// replies to parentID
+func (s *CommentService) List(ctx context.Context, taskID, parentID, cursor string, perPage int) (*model.CommentListResponse, error) {
+	if perPage < 1 || perPage > model.MaxPerPage {
+		perPage = model.DefaultPerPage
+	}
+	q := model.CommentListQuery{TaskID: taskID, ParentID: parentID, PerPage: perPage}
+	if cursor != "" {
+		c, err := model.DecodeCommentCursor(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: cursor: %v", ErrValidation, err)
+		}
+		q.Cursor = c
+	}
+
+	if _, err := s.tasks.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+
+	page, err := s.commentStore.ListComments(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("service: list comments: %w", err)
+	}
+
+	resp := &model.CommentListResponse{Data: page.Comments, PerPage: perPage}
+	if resp.Data == nil {
+		resp.Data = []model.Comment{}
+	}
+	if page.HasMore {
+		last := page.Comments[len(page.Comments)-1]
+		resp.NextCursor = model.CommentCursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return resp, nil
+}
+
+// Update edits a comment; the previous body is kept in its history. Only
+// the author or a moderator may edit a comment.
+func (s *CommentService) Update(ctx context.Context, taskID, id string, req model.CommentUpdateRequest) (*model.Comment, error) {
+	body, err := commentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAuthor(ctx, taskID, id); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentStore.UpdateComment(ctx, taskID, id, body)
+	if err != nil {
+		return nil, fmt.Errorf("service: update comment: %w", err)
+	}
+	return comment, nil
+}
+
+// Delete removes a comment. Only the author or a moderator may delete a
+// comment.
+func (s *CommentService) Delete(ctx context.Context, taskID, id string) error {
+	if err := s.checkAuthor(ctx, taskID, id); err != nil {
+		return err
+	}
+
+	if err := s.commentStore.DeleteComment(ctx, taskID, id); err != nil {
+		return fmt.Errorf("service: delete comment: %w", err)
+	}
+	return nil
+}
+
+// checkAuthor returns ErrCommentForbidden unless the request's principal
+// wrote the comment or may moderate comments
+func (s *CommentService) checkAuthor(ctx context.Context, taskID, id string) error {
+	comment, err := s.commentStore.GetComment(ctx, taskID, id)
+	if err != nil {
+		return fmt.Errorf("service: get comment: %w", err)
+	}
+
+	principal, _ := auth.FromContext(ctx)
+	if comment.Author != "" && comment.Author == principal.Subject {
+		return nil
+	}
+	if principal.Can(auth.PermModerateComments) {
+		return nil
+	}
+	return ErrCommentForbidden
+}
+
+// commentBody trims a comment body and rejects blank ones
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body: must not be blank", ErrValidation)
+	}
+	return body, nil
+}
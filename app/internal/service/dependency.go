@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// AddBlocker records that a task is blocked by another task of the tenant.
+// Dependencies that would make a task wait on itself are rejected.
+func (s *TaskService) AddBlocker(ctx context.Context, taskID, blockerID string) (*model.TaskDependency, error) {
+	dep, err := s.dependencyStore.Add(ctx, taskID, blockerID)
+	if errors.Is(err, ErrInvalidDependency) {
+		// The store explains what is wrong with the blocker to the client
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: add blocker: %w", err)
+	}
+
+	s.logActivities(ctx, []model.ActivityLog{{TaskID: taskID, Action: "blocker_added",
+		Details: fmt.Sprintf("Task blocked by %s", blockerID), To: blockerID}})
+
+	return dep, nil
+}
+
+// RemoveBlocker deletes a dependency
+func (s *TaskService) RemoveBlocker(ctx context.Context, taskID, blockerID string) error {
+	if err := s.dependencyStore.Remove(ctx, taskID, blockerID); err != nil {
+		return fmt.Errorf("service: remove blocker: %w", err)
+	}
+
+	s.logActivities(ctx, []model.ActivityLog{{TaskID: taskID, Action: "blocker_removed",
+		Details: fmt.Sprintf("Task no longer blocked by %s", blockerID), From: blockerID}})
+
+	return nil
+}
+
+// Blockers returns the tasks blocking a task, open or not
+func (s *TaskService) Blockers(ctx context.Context, taskID string) ([]model.Task, error) {
+	if _, err := s.taskStore.GetByID(ctx, taskID); err != nil {
+		return nil, fmt.Errorf("service: get task: %w", err)
+	}
+
+	blockers, err := s.dependencyStore.Blockers(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("service: list blockers: %w", err)
+	}
+	if blockers == nil {
+		blockers = []model.Task{}
+	}
+	return blockers, nil
+}
+
+// ExecutionOrder sorts tasks topologically so that every task comes after
+// its blockers, considering only the dependencies among the given tasks.
+// Tasks become ready stage by stage; within a stage higher priorities and
+// then older tasks go first.
+func (s *TaskService) ExecutionOrder(ctx context.Context, ids []string) (*model.ExecutionOrderResponse, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > model.MaxExecutionOrderTasks {
+		return nil, fmt.Errorf("%w: ids: at most %d tasks", ErrValidation, model.MaxExecutionOrderTasks)
+	}
+
+	page, err := s.taskStore.List(ctx, model.TaskListQuery{
+		PerPage: len(ids),
+		Filter:  model.TaskFilter{IDs: ids},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("service: list tasks: %w", err)
+	}
+	tasks := make(map[string]model.Task, len(page.Tasks))
+	for _, t := range page.Tasks {
+		tasks[t.ID] = t
+	}
+	for _, id := range ids {
+		if _, ok := tasks[id]; !ok {
+			return nil, fmt.Errorf("%w: ids: task %s not found", ErrValidation, id)
+		}
+	}
+
+	deps, err := s.dependencyStore.Edges(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service: list dependencies: %w", err)
+	}
+	waiting := make(map[string]int, len(ids)) // number of blockers not yet scheduled
+	blocks := make(map[string][]string)       // blocker → tasks it blocks
+	for _, d := range deps {
+		waiting[d.TaskID]++
+		blocks[d.BlockerID] = append(blocks[d.BlockerID], d.TaskID)
+	}
+
+	resp := &model.ExecutionOrderResponse{Data: make([]model.Task, 0, len(ids)), Stages: [][]string{}}
+	var ready []model.Task
+	for _, id := range ids {
+		if waiting[id] == 0 {
+			ready = append(ready, tasks[id])
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return runsBefore(ready[i], ready[j]) })
+
+		stage := make([]string, len(ready))
+		var next []model.Task
+		for i, t := range ready {
+			stage[i] = t.ID
+			resp.Data = append(resp.Data, t)
+			for _, blocked := range blocks[t.ID] {
+				if waiting[blocked]--; waiting[blocked] == 0 {
+					next = append(next, tasks[blocked])
+				}
+			}
+		}
+		resp.Stages = append(resp.Stages, stage)
+		ready = next
+	}
+
+	// The stores reject cycles, so every task is scheduled
+	if len(resp.Data) != len(ids) {
+		return nil, fmt.Errorf("service: execution order: %w", ErrDependencyCycle)
+	}
+	return resp, nil
+}
+
+// runsBefore orders the tasks of one stage: higher priority first, then older
+func runsBefore(a, b model.Task) bool {
+	if ra, rb := model.PriorityRank(a.Priority), model.PriorityRank(b.Priority); ra != rb {
+		return ra > rb
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
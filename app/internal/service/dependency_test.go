@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// edge is a dependency in test tables: task is blocked by blocker
+type edge struct{ task, blocker string }
+
+func TestAddBlockerRejectsCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []edge
+		add      edge
+		err      error
+	}{
+		{"independent tasks", nil, edge{"a", "b"}, nil},
+		{"blocked by itself", nil, edge{"a", "a"}, ErrInvalidDependency},
+		{"unknown blocker", nil, edge{"a", "missing"}, ErrInvalidDependency},
+		{"already blocked", []edge{{"a", "b"}}, edge{"a", "b"}, ErrDependencyExists},
+		{"two-task cycle", []edge{{"a", "b"}}, edge{"b", "a"}, ErrDependencyCycle},
+		{"three-task cycle", []edge{{"a", "b"}, {"b", "c"}}, edge{"c", "a"}, ErrDependencyCycle},
+		{"cycle through a branch", []edge{{"a", "b"}, {"a", "c"}, {"c", "d"}}, edge{"d", "a"}, ErrDependencyCycle},
+		{"diamond", []edge{{"a", "b"}, {"a", "c"}, {"b", "d"}}, edge{"c", "d"}, nil},
+		{"shortcut along a chain", []edge{{"a", "b"}, {"b", "c"}}, edge{"a", "c"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTaskService(t)
+			ctx := testContext(tenant.Default)
+			ids := map[string]string{"missing": "00000000-0000-0000-0000-000000000000"}
+			for _, name := range []string{"a", "b", "c", "d"} {
+				ids[name] = createTask(t, ctx, s, name).ID
+			}
+			for _, e := range tt.existing {
+				if _, err := s.AddBlocker(ctx, ids[e.task], ids[e.blocker]); err != nil {
+					t.Fatalf("AddBlocker(%s, %s) error = %v", e.task, e.blocker, err)
+				}
+			}
+
+			_, err := s.AddBlocker(ctx, ids[tt.add.task], ids[tt.add.blocker])
+			if tt.err == nil && err != nil {
+				t.Fatalf("AddBlocker(%s, %s) error = %v", tt.add.task, tt.add.blocker, err)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("AddBlocker(%s, %s) error = %v, want %v", tt.add.task, tt.add.blocker, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestExecutionOrderStages(t *testing.T) {
+	tasks := []struct{ name, priority string }{
+		{"design", "medium"},
+		{"build", "low"},
+		{"migrate", "critical"},
+		{"deploy", "high"},
+		{"docs", "high"},
+	}
+	edges := []edge{
+		{"build", "design"},
+		{"migrate", "design"},
+		{"deploy", "build"},
+		{"deploy", "migrate"},
+	}
+
+	tests := []struct {
+		name   string
+		sort   []string
+		stages [][]string
+	}{
+		{
+			name:   "stages follow the blockers, priorities order each stage",
+			sort:   []string{"deploy", "docs", "build", "migrate", "design"},
+			stages: [][]string{{"docs", "design"}, {"migrate", "build"}, {"deploy"}},
+		},
+		{
+			name:   "blockers outside the request are ignored",
+			sort:   []string{"deploy", "build", "migrate"},
+			stages: [][]string{{"migrate", "build"}, {"deploy"}},
+		},
+		{
+			name:   "repeated IDs count once",
+			sort:   []string{"build", "design", "build"},
+			stages: [][]string{{"design"}, {"build"}},
+		},
+	}
+
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	ids := map[string]string{}
+	names := map[string]string{}
+	for _, task := range tasks {
+		created, err := s.Create(ctx, model.TaskCreateRequest{Title: task.name, Priority: task.priority})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", task.name, err)
+		}
+		ids[task.name], names[created.ID] = created.ID, task.name
+	}
+	for _, e := range edges {
+		if _, err := s.AddBlocker(ctx, ids[e.task], ids[e.blocker]); err != nil {
+			t.Fatalf("AddBlocker(%s, %s) error = %v", e.task, e.blocker, err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request []string
+			for _, name := range tt.sort {
+				request = append(request, ids[name])
+			}
+
+			resp, err := s.ExecutionOrder(ctx, request)
+			if err != nil {
+				t.Fatalf("ExecutionOrder() error = %v", err)
+			}
+
+			var stages [][]string
+			var flat, order []string
+			for _, stage := range resp.Stages {
+				var named []string
+				for _, id := range stage {
+					named = append(named, names[id])
+				}
+				stages = append(stages, named)
+				flat = append(flat, named...)
+			}
+			for _, task := range resp.Data {
+				order = append(order, names[task.ID])
+			}
+			if fmt.Sprint(stages) != fmt.Sprint(tt.stages) {
+				t.Errorf("stages = %v, want %v", stages, tt.stages)
+			}
+			if fmt.Sprint(order) != fmt.Sprint(flat) {
+				t.Errorf("data order = %v, want the stages in sequence %v", order, flat)
+			}
+		})
+	}
+
+	t.Run("unknown task", func(t *testing.T) {
+		_, err := s.ExecutionOrder(ctx, []string{ids["build"], "00000000-0000-0000-0000-000000000000"})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("ExecutionOrder() error = %v, want ErrValidation", err)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change of a
+// unified diff
+const diffContext = 3
+
+// maxDiffCells bounds the table unifiedDiff fills in; texts with more lines
+// are shown as one replacement
+const maxDiffCells = 1 << 22
+
+// diffLine is one line of a diff: kept (' '), removed ('-') or added ('+').
+// a and b count the lines of the old and new text that precede it.
+type diffLine struct {
+	kind byte
+	text string
+	a, b int
+}
+
+// unifiedDiff renders the line changes from before to after as a unified
+// diff; equal texts give ""
+func unifiedDiff(before, after string) string {
+	if before == after {
+		return ""
+	}
+	lines := diffLines(splitLines(before), splitLines(after))
+
+	var sb strings.Builder
+	sb.WriteString("--- before\n+++ after\n")
+	for i := 0; i < len(lines); {
+		if lines[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk over changes separated by little enough context
+		// that their surroundings would overlap
+		end := i + 1
+		for j := end; j < len(lines) && j-end <= 2*diffContext; j++ {
+			if lines[j].kind != ' ' {
+				end = j + 1
+			}
+		}
+		start, stop := max(i-diffContext, 0), min(end+diffContext, len(lines))
+
+		var oldCount, newCount int
+		for _, l := range lines[start:stop] {
+			if l.kind != '+' {
+				oldCount++
+			}
+			if l.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(lines[start].a, oldCount), hunkRange(lines[start].b, newCount))
+		for _, l := range lines[start:stop] {
+			sb.WriteByte(l.kind)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// hunkRange formats the line range of a hunk that follows the first
+// preceding lines and spans count lines
+func hunkRange(preceding, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", preceding)
+	case 1:
+		return fmt.Sprintf("%d", preceding+1)
+	}
+	return fmt.Sprintf("%d,%d", preceding+1, count)
+}
+
+// diffLines aligns a and b along their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	if (n+1)*(m+1) > maxDiffCells {
+		lines := make([]diffLine, 0, n+m)
+		for i, text := range a {
+			lines = append(lines, diffLine{kind: '-', text: text, a: i})
+		}
+		for j, text := range b {
+			lines = append(lines, diffLine{kind: '+', text: text, a: n, b: j})
+		}
+		return lines
+	}
+
+	// lcs[i*(m+1)+j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			} else {
+				lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			lines = append(lines, diffLine{kind: ' ', text: a[i], a: i, b: j})
+			i++
+			j++
+		case j == m || (i < n && lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]):
+			lines = append(lines, diffLine{kind: '-', text: a[i], a: i, b: j})
+			i++
+		default:
+			lines = append(lines, diffLine{kind: '+', text: b[j], a: i, b: j})
+			j++
+		}
+	}
+	return lines
+}
+
+// splitLines splits text into lines, ignoring a final newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
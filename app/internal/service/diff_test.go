@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	numbered := func(prefix string, from, to int) []string {
+		var lines []string
+		for i := from; i <= to; i++ {
+			lines = append(lines, fmt.Sprintf("%s%d", prefix, i))
+		}
+		return lines
+	}
+	text := func(parts ...[]string) string {
+		var lines []string
+		for _, p := range parts {
+			lines = append(lines, p...)
+		}
+		return strings.Join(lines, "\n")
+	}
+	header := "--- before\n+++ after\n"
+
+	tests := []struct {
+		name          string
+		before, after string
+		want          string
+	}{
+		{
+			name:   "equal",
+			before: "same\ntext",
+			after:  "same\ntext",
+			want:   "",
+		},
+		{
+			name:   "one line replaced",
+			before: "a\nb\nc",
+			after:  "a\nB\nc",
+			want:   header + "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:   "from empty",
+			before: "",
+			after:  "x\ny",
+			want:   header + "@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name:   "to empty",
+			before: "x",
+			after:  "",
+			want:   header + "@@ -1 +0,0 @@\n-x\n",
+		},
+		{
+			name:   "final newline is not a line",
+			before: "a\n",
+			after:  "a",
+			want:   header,
+		},
+		{
+			name:   "distant changes get their own hunks",
+			before: text(numbered("l", 1, 10)),
+			after:  text([]string{"L1"}, numbered("l", 2, 9), []string{"L10"}),
+			want: header +
+				"@@ -1,4 +1,4 @@\n-l1\n+L1\n l2\n l3\n l4\n" +
+				"@@ -7,4 +7,4 @@\n l7\n l8\n l9\n-l10\n+L10\n",
+		},
+		{
+			name:   "nearby changes share a hunk",
+			before: text(numbered("l", 1, 9)),
+			after:  text([]string{"l1", "L2"}, numbered("l", 3, 7), []string{"L8", "l9"}),
+			want: header +
+				"@@ -1,9 +1,9 @@\n l1\n-l2\n+L2\n l3\n l4\n l5\n l6\n l7\n-l8\n+L8\n l9\n",
+		},
+		{
+			name:   "insertion keeps its context",
+			before: text(numbered("l", 1, 8)),
+			after:  text(numbered("l", 1, 4), []string{"new"}, numbered("l", 5, 8)),
+			want:   header + "@@ -2,6 +2,7 @@\n l2\n l3\n l4\n+new\n l5\n l6\n l7\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff(tt.before, tt.after); got != tt.want {
+				t.Fatalf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// Texts too large to align are shown as one replacement
+func TestUnifiedDiffOversized(t *testing.T) {
+	before := strings.Repeat("same\n", 2100) + "old"
+	after := strings.Repeat("same\n", 2100) + "new"
+
+	got := unifiedDiff(before, after)
+	if !strings.HasPrefix(got, "--- before\n+++ after\n@@ -1,2101 +1,2101 @@\n-same\n") {
+		t.Fatalf("unifiedDiff() starts with %q, want the whole text replaced", got[:min(len(got), 80)])
+	}
+	if n := strings.Count(got, "\n-"); n != 2101 {
+		t.Errorf("unifiedDiff() removes %d lines, want 2101", n)
+	}
+}
+
+// The diff is built for the history view only and never reaches the store
+func TestHistoryDiffsDescriptions(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, s, "diffed")
+
+	description := "first line\nsecond line"
+	if _, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Description: &description}, 0); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	history, err := s.History(ctx, task.ID, 10)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(history) == 0 || history[0].Action != "updated" || len(history[0].Changes) != 1 {
+		t.Fatalf("History() = %+v, want the update first", history)
+	}
+	want := "--- before\n+++ after\n@@ -0,0 +1,2 @@\n+first line\n+second line\n"
+	if got := history[0].Changes[0].Diff; got != want {
+		t.Errorf("description diff = %q, want %q", got, want)
+	}
+
+	logs, err := s.GetActivities(ctx, task.ID, 10)
+	if err != nil {
+		t.Fatalf("GetActivities() error = %v", err)
+	}
+	for _, l := range logs {
+		for _, c := range l.Changes {
+			if c.Diff != "" {
+				t.Fatalf("activity %s carries a diff: %q", l.ID, c.Diff)
+			}
+		}
+	}
+}
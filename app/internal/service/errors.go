@@ -0,0 +1,122 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// Errors callers can match with errors.Is
+var (
+	ErrTaskNotFound    = repository.ErrTaskNotFound
+	ErrVersionConflict = repository.ErrVersionConflict
+	ErrBatchAborted    = repository.ErrBatchAborted
+	ErrUserNotFound    = repository.ErrUserNotFound
+	ErrUserExists      = repository.ErrUserExists
+	ErrAPIKeyNotFound  = repository.ErrAPIKeyNotFound
+	ErrProjectNotFound = repository.ErrProjectNotFound
+	ErrProjectExists   = repository.ErrProjectExists
+	ErrProjectNotEmpty = repository.ErrProjectNotEmpty
+	ErrInvalidParent   = repository.ErrInvalidParent
+	ErrHasSubtasks     = repository.ErrHasSubtasks
+	ErrParentInTrash   = repository.ErrParentInTrash
+
+	ErrInvalidDependency  = repository.ErrInvalidDependency
+	ErrDependencyExists   = repository.ErrDependencyExists
+	ErrDependencyNotFound = repository.ErrDependencyNotFound
+	ErrDependencyCycle    = repository.ErrDependencyCycle
+
+	ErrCommentNotFound      = repository.ErrCommentNotFound
+	ErrInvalidCommentParent = repository.ErrInvalidCommentParent
+	ErrAttachmentNotFound   = repository.ErrAttachmentNotFound
+	ErrBlobNotFound         = repository.ErrBlobNotFound
+
+	// ErrValidation wraps input that fails the request validation rules
+	ErrValidation = errors.New("validation failed")
+	// ErrUnsupportedPatch is returned for an unknown PATCH content type
+	ErrUnsupportedPatch = errors.New("unsupported patch content type")
+	// ErrInvalidPatch is returned for a malformed or inapplicable patch document
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrPatchTestFailed is returned when a JSON Patch "test" operation fails
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+	// ErrInvalidTransition is returned when a status change is not allowed by the workflow
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrOpenSubtasks is returned when completing a task whose subtasks are still open
+	ErrOpenSubtasks = errors.New("task has open subtasks")
+	// ErrBlocked is returned when starting or completing a task with open blockers
+	ErrBlocked = errors.New("task is blocked")
+	// ErrCommentForbidden is returned when changing a comment someone else wrote
+	ErrCommentForbidden = errors.New("only the author or a moderator may change this comment")
+	// ErrAttachmentTooLarge is returned when an upload exceeds the size limit
+	ErrAttachmentTooLarge = errors.New("attachment too large")
+	// ErrChecksumMismatch is returned when content does not match its SHA-256 checksum
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrRevertTargetNotFound is returned when a revert names an activity that
+	// is not a create or update of the task
+	ErrRevertTargetNotFound = errors.New("revert target not found")
+	// ErrHistoryIncomplete is returned when a revert would have to roll back
+	// an update logged without its field changes
+	ErrHistoryIncomplete = errors.New("task history is incomplete")
+)
+
+// TransitionError describes a status change rejected by the workflow
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move task from %s to %s", e.From, e.To)
+}
+
+// Is makes a TransitionError match ErrInvalidTransition
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// BlockedError describes a status change refused because of open blockers
+type BlockedError struct {
+	Status   string
+	Blockers []string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("cannot move task to %s: blocked by %d open tasks", e.Status, len(e.Blockers))
+}
+
+// Is makes a BlockedError match ErrBlocked
+func (e *BlockedError) Is(target error) bool {
+	return target == ErrBlocked
+}
+
+// OpenSubtasksError describes a completion refused because of open subtasks
+type OpenSubtasksError struct {
+	Open int
+}
+
+func (e *OpenSubtasksError) Error() string {
+	return fmt.Sprintf("cannot complete task: %d subtasks are still open", e.Open)
+}
+
+// Is makes an OpenSubtasksError match ErrOpenSubtasks
+func (e *OpenSubtasksError) Is(target error) bool {
+	return target == ErrOpenSubtasks
+}
+
+// ChecksumError describes an upload whose content differs from the checksum
+// the client sent
+type ChecksumError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("content has SHA-256 %s, expected %s", e.Actual, e.Expected)
+}
+
+// Is makes a ChecksumError match ErrChecksumMismatch
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// guardUpdate returns a Modify callback that applies mutate, normalizes the
+// tags and rejects the result if its status change is not allowed by the
+// workflow, its dates are inconsistent, its tags are invalid or it references
+// an unknown user or an unknown or archived project, or if open work forbids
+// its new status. open is collected beforehand (see countOpenWork); the store
+// holds the task locked while the callback runs, so it cannot look it up
+// itself; users and projects are read while it is locked, so their stores
+// must not wait for the task store while holding their own locks. The task
+// as it was before the change is copied to prev.
+func (s *TaskService) guardUpdate(ctx context.Context, prev *model.Task, open openWork, mutate func(*model.Task) error) func(*model.Task) error {
+	return func(t *model.Task) error {
+		*prev = *t
+		if err := mutate(t); err != nil {
+			return err
+		}
+		tags, err := normalizeTags(t.Tags)
+		if err != nil {
+			return err
+		}
+		t.Tags = tags
+		if !s.workflow.CanTransition(prev.Status, t.Status) {
+			return &TransitionError{From: prev.Status, To: t.Status, Allowed: s.workflow.Allowed(prev.Status)}
+		}
+		if t.Status != prev.Status {
+			if len(open.blockers) > 0 && model.NeedsUnblocked(t.Status) {
+				return &BlockedError{Status: t.Status, Blockers: open.blockers}
+			}
+			if open.subtasks > 0 && t.Status == "completed" {
+				return &OpenSubtasksError{Open: open.subtasks}
+			}
+		}
+		if err := checkSchedule(t.StartAt, t.DueAt); err != nil {
+			return err
+		}
+
+		// Only changed references need checking; existing ones are kept valid by the store
+		if changed(prev.AssigneeID, t.AssigneeID) {
+			if err := s.checkUser(ctx, "assignee_id", t.AssigneeID); err != nil {
+				return err
+			}
+		}
+		if changed(prev.ReporterID, t.ReporterID) {
+			if err := s.checkUser(ctx, "reporter_id", t.ReporterID); err != nil {
+				return err
+			}
+		}
+		if changed(prev.ProjectID, t.ProjectID) {
+			if err := s.checkProject(ctx, t.ProjectID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// openWork is what still has to be closed before a task may change status
+type openWork struct {
+	// subtasks counts the open subtasks at every depth
+	subtasks int
+	// blockers lists the IDs of the open tasks blocking the task
+	blockers []string
+}
+
+// countOpenWork collects the open work that could stop a task from moving to
+// status; pass anyStatus when the write may set any status, as patches do.
+// Open subtasks are only counted if the rules forbid completing a task with
+// open subtasks. Nothing is queried when no check applies.
+func (s *TaskService) countOpenWork(ctx context.Context, id string, status *string) (openWork, error) {
+	var open openWork
+	if status == nil {
+		return open, nil
+	}
+	unknown := status == anyStatus
+
+	if (unknown || *status == "completed") && !s.subtasks.CompleteWithOpenSubtasks {
+		progress, err := s.taskStore.SubtaskProgress(ctx, []string{id})
+		if err != nil {
+			return open, fmt.Errorf("service: count subtasks: %w", err)
+		}
+		open.subtasks = progress[id].Open()
+	}
+
+	if unknown || model.NeedsUnblocked(*status) {
+		blockers, err := s.dependencyStore.Blockers(ctx, id)
+		if err != nil {
+			return open, fmt.Errorf("service: list blockers: %w", err)
+		}
+		for _, b := range blockers {
+			if b.IsOpen() {
+				open.blockers = append(open.blockers, b.ID)
+			}
+		}
+	}
+
+	return open, nil
+}
+
+// anyStatus tells countOpenWork that the write may set any status
+var anyStatus = new(string)
+
+// normalizeTags cleans up a tag list, reporting invalid tags as ErrValidation
+func normalizeTags(tags []string) ([]string, error) {
+	normalized, err := model.NormalizeTags(tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: tags: %v", ErrValidation, err)
+	}
+	return normalized, nil
+}
+
+// checkSchedule rejects a due date before the start date
+func checkSchedule(start, due *time.Time) error {
+	if start != nil && due != nil && due.Before(*start) {
+		return fmt.Errorf("%w: due_at must not be before start_at", ErrValidation)
+	}
+	return nil
+}
+
+// checkUser verifies that a user reference, if set, points at an existing user
+func (s *TaskService) checkUser(ctx context.Context, field string, id *string) error {
+	if id == nil {
+		return nil
+	}
+
+	_, err := s.userStore.GetByID(ctx, *id)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("%w: %s: user %s not found", ErrValidation, field, *id)
+	}
+	if err != nil {
+		return fmt.Errorf("service: check %s: %w", field, err)
+	}
+	return nil
+}
+
+// checkProject verifies that a project reference, if set, points at an
+// active project of the tenant; archived projects take no new tasks
+func (s *TaskService) checkProject(ctx context.Context, id *string) error {
+	if id == nil {
+		return nil
+	}
+
+	project, err := s.projectStore.GetByID(ctx, *id)
+	if errors.Is(err, repository.ErrProjectNotFound) {
+		return fmt.Errorf("%w: project_id: project %s not found", ErrValidation, *id)
+	}
+	if err != nil {
+		return fmt.Errorf("service: check project_id: %w", err)
+	}
+	if project.Archived {
+		return fmt.Errorf("%w: project_id: project %s is archived", ErrValidation, *id)
+	}
+	return nil
+}
+
+// checkCreate normalizes the tags of a create request and validates it
+// against the current users and projects
+func (s *TaskService) checkCreate(ctx context.Context, req *model.TaskCreateRequest) error {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return err
+	}
+	req.Tags = tags
+
+	if err := checkSchedule(req.StartAt, req.DueAt); err != nil {
+		return err
+	}
+	if err := s.checkUser(ctx, "assignee_id", req.AssigneeID); err != nil {
+		return err
+	}
+	if err := s.checkUser(ctx, "reporter_id", req.ReporterID); err != nil {
+		return err
+	}
+	return s.checkProject(ctx, req.ProjectID)
+}
+
+// changed reports whether two optional IDs differ
+func changed(a, b *string) bool {
+	return stringOrEmpty(a) != stringOrEmpty(b)
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// updateActivities builds the activity entries for a task update: an
+// "updated" entry listing the changed fields, plus "status_changed",
+// "reassigned" and "tags_changed" entries when the status, assignee or tags
+// moved. prev may be nil if the previous state is unknown.
+func updateActivities(task, prev *model.Task, details string) []model.ActivityLog {
+	logs := []model.ActivityLog{{TaskID: task.ID, Action: "updated", Details: details, Version: task.Version}}
+	if prev == nil || prev.ID == "" {
+		return logs
+	}
+	logs[0].Changes = model.DiffTaskFields(*prev, *task)
+
+	if prev.Status != task.Status {
+		logs = append(logs, model.ActivityLog{
+			TaskID:  task.ID,
+			Action:  "status_changed",
+			Details: fmt.Sprintf("Task '%s' moved from %s to %s", task.Title, prev.Status, task.Status),
+			From:    prev.Status,
+			To:      task.Status,
+		})
+	}
+	if changed(prev.AssigneeID, task.AssigneeID) {
+		logs = append(logs, reassignedActivity(task, prev.AssigneeID, task.AssigneeID))
+	}
+	if added, removed := model.DiffTags(prev.Tags, task.Tags); len(added) > 0 || len(removed) > 0 {
+		logs = append(logs, tagsChangedActivity(task, prev.Tags, added, removed))
+	}
+
+	return logs
+}
+
+// tagsChangedActivity records a change of tags; From and To hold the
+// comma-separated tags before and after
+func tagsChangedActivity(task *model.Task, before, added, removed []string) model.ActivityLog {
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+
+	return model.ActivityLog{
+		TaskID:  task.ID,
+		Action:  "tags_changed",
+		Details: fmt.Sprintf("Task '%s' tags changed: %s", task.Title, strings.Join(changes, "; ")),
+		From:    strings.Join(before, ","),
+		To:      strings.Join(task.Tags, ","),
+	}
+}
+
+// subtaskActivities records what deleting parent did to its subtasks: they
+// were either moved to the trash with it or detached and became top-level
+// tasks
+func subtaskActivities(parent *model.Task, subtasks []model.Task, onSubtasks string) []model.ActivityLog {
+	logs := make([]model.ActivityLog, 0, len(subtasks))
+	for _, t := range subtasks {
+		entry := model.ActivityLog{
+			TaskID:  t.ID,
+			Action:  "deleted",
+			Details: fmt.Sprintf("Task '%s' moved to trash with its parent '%s'", t.Title, parent.Title),
+		}
+		if onSubtasks == model.SubtasksOrphan {
+			entry.Action = "detached"
+			entry.Details = fmt.Sprintf("Task '%s' detached from its deleted parent '%s'", t.Title, parent.Title)
+			entry.From = parent.ID
+		}
+		logs = append(logs, entry)
+	}
+	return logs
+}
+
+// reassignedActivity records an assignee change; From and To are empty when
+// the task was or became unassigned
+func reassignedActivity(task *model.Task, from, to *string) model.ActivityLog {
+	name := func(id *string) string {
+		if id == nil {
+			return "nobody"
+		}
+		return *id
+	}
+
+	return model.ActivityLog{
+		TenantID: task.TenantID,
+		TaskID:   task.ID,
+		Action:   "reassigned",
+		Details:  fmt.Sprintf("Task '%s' reassigned from %s to %s", task.Title, name(from), name(to)),
+		From:     stringOrEmpty(from),
+		To:       stringOrEmpty(to),
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin/binding"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// applyTaskPatch applies a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902)
+// document to task, validating the result with the TaskUpdateRequest rules
+func applyTaskPatch(task *model.Task, contentType string, patch []byte) error {
+	original, err := json.Marshal(model.TaskPatchDocument{
+		Title:       task.Title,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		StartAt:     task.StartAt,
+		DueAt:       task.DueAt,
+		AssigneeID:  task.AssigneeID,
+		ReporterID:  task.ReporterID,
+		ProjectID:   task.ProjectID,
+		ParentID:    task.ParentID,
+		Tags:        tagList(task.Tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	var patched []byte
+	switch contentType {
+	case model.MergePatchContentType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+
+	case model.JSONPatchContentType:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		patched, err = ops.Apply(original)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return fmt.Errorf("%w: %v", ErrPatchTestFailed, err)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedPatch, contentType)
+	}
+
+	// Reject members that are not patchable, such as /id or /version
+	var doc model.TaskPatchDocument
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	if err := validatePatchDocument(doc); err != nil {
+		return err
+	}
+
+	task.Title = doc.Title
+	task.Description = doc.Description
+	task.Status = doc.Status
+	task.Priority = doc.Priority
+	task.StartAt = doc.StartAt
+	task.DueAt = doc.DueAt
+	task.AssigneeID = doc.AssigneeID
+	task.ReporterID = doc.ReporterID
+	task.ProjectID = doc.ProjectID
+	task.ParentID = doc.ParentID
+	task.Tags = doc.Tags
+	return nil
+}
+
+// tagList returns tags, or an empty list instead of nil so JSON Patch can
+// append to /tags
+func tagList(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// validatePatchDocument applies the TaskUpdateRequest binding rules. Unlike a
+// PUT body, a patched document may not leave required fields empty.
+func validatePatchDocument(doc model.TaskPatchDocument) error {
+	for field, value := range map[string]string{
+		"title": doc.Title, "status": doc.Status, "priority": doc.Priority,
+	} {
+		if value == "" {
+			return fmt.Errorf("%w: %s is required", ErrValidation, field)
+		}
+	}
+
+	req := model.TaskUpdateRequest{
+		Title:       &doc.Title,
+		Description: &doc.Description,
+		Status:      &doc.Status,
+		Priority:    &doc.Priority,
+	}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return fmt.Errorf("%w: %v", ErrValidation, err)
+	}
+
+	return nil
+}
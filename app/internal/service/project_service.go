@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// ProjectService handles business logic for projects
+type ProjectService struct {
+	projectStore repository.ProjectStore
+	userStore    repository.UserStore
+	summaryCache repository.ProjectSummaryCache
+	logger       *zap.Logger
+}
+
+// NewProjectService creates a new project service
+func NewProjectService(
+	projects repository.ProjectStore,
+	users repository.UserStore,
+	summaries repository.ProjectSummaryCache,
+	logger *zap.Logger,
+) *ProjectService {
+	return &ProjectService{
+		projectStore: projects,
+		userStore:    users,
+		summaryCache: summaries,
+		logger:       logger,
+	}
+}
+
+// Create creates a new project
+func (s *ProjectService) Create(ctx context.Context, req model.ProjectCreateRequest) (*model.Project, error) {
+	if err := s.checkOwner(ctx, req.OwnerID); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectStore.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("service: create project: %w", err)
+	}
+	return project, nil
+}
+
+// GetByID retrieves a project by ID
+func (s *ProjectService) GetByID(ctx context.Context, id string) (*model.Project, error) {
+	project, err := s.projectStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get project: %w", err)
+	}
+	return project, nil
+}
+
+// List retrieves one page of projects
+func (s *ProjectService) List(ctx context.Context, page, perPage int, includeArchived bool) (*model.ProjectListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > model.MaxPerPage {
+		perPage = model.DefaultPerPage
+	}
+
+	projects, total, err := s.projectStore.List(ctx, page, perPage, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("service: list projects: %w", err)
+	}
+	if projects == nil {
+		projects = []model.Project{}
+	}
+
+	return &model.ProjectListResponse{
+		Data:    projects,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// Update modifies a project. An empty owner ID clears the owner.
+func (s *ProjectService) Update(ctx context.Context, id string, req model.ProjectUpdateRequest) (*model.Project, error) {
+	if req.OwnerID != nil && *req.OwnerID != "" {
+		if err := s.checkOwner(ctx, req.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	project, err := s.projectStore.Update(ctx, id, req)
+	if err != nil {
+		return nil, fmt.Errorf("service: update project: %w", err)
+	}
+	return project, nil
+}
+
+// Delete removes a project that has no tasks
+func (s *ProjectService) Delete(ctx context.Context, id string) error {
+	if err := s.projectStore.Delete(ctx, id); err != nil {
+		return fmt.Errorf("service: delete project: %w", err)
+	}
+
+	if cacheErr := s.summaryCache.InvalidateProjectSummaries(ctx, []string{id}); cacheErr != nil {
+		s.logger.Warn("failed to invalidate project summary", zap.Error(cacheErr))
+	}
+	return nil
+}
+
+// Summary returns the task counts and progress of a project with
+// cache-aside; task writes invalidate the cached copy
+func (s *ProjectService) Summary(ctx context.Context, id string) (*model.ProjectSummary, error) {
+	cached, err := s.summaryCache.GetProjectSummary(ctx, id)
+	if err != nil {
+		s.logger.Warn("project summary cache lookup failed", zap.Error(err))
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	summary, err := s.projectStore.Summary(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: summarize project: %w", err)
+	}
+
+	if cacheErr := s.summaryCache.SetProjectSummary(ctx, summary); cacheErr != nil {
+		s.logger.Warn("failed to cache project summary", zap.Error(cacheErr))
+	}
+	return summary, nil
+}
+
+// checkOwner verifies that an owner reference, if set, points at an existing user
+func (s *ProjectService) checkOwner(ctx context.Context, id *string) error {
+	if id == nil {
+		return nil
+	}
+
+	_, err := s.userStore.GetByID(ctx, *id)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return fmt.Errorf("%w: owner_id: user %s not found", ErrValidation, *id)
+	}
+	if err != nil {
+		return fmt.Errorf("service: check owner_id: %w", err)
+	}
+	return nil
+}
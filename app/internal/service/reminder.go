@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// reminderBatchSize caps how many tasks one claim hands out
+const reminderBatchSize = 100
+
+// ReminderScheduler periodically logs "due_soon" and "overdue" activities
+// for open tasks with a due date. Each reminder is sent once per due date:
+// moving the deadline re-arms it.
+type ReminderScheduler struct {
+	taskStore     repository.TaskStore
+	activityStore repository.ActivityStore
+	interval      time.Duration
+	window        time.Duration
+	logger        *zap.Logger
+}
+
+// NewReminderScheduler creates a scheduler that runs every interval and warns
+// about tasks due within window
+func NewReminderScheduler(
+	tasks repository.TaskStore,
+	activities repository.ActivityStore,
+	interval, window time.Duration,
+	logger *zap.Logger,
+) *ReminderScheduler {
+	return &ReminderScheduler{
+		taskStore:     tasks,
+		activityStore: activities,
+		interval:      interval,
+		window:        window,
+		logger:        logger,
+	}
+}
+
+// Run sends reminders until ctx is cancelled
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.SendReminders(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendReminders logs every reminder due at now, in every tenant
+func (s *ReminderScheduler) SendReminders(ctx context.Context, now time.Time) {
+	ctx = tenant.WithID(ctx, tenant.All)
+
+	// Overdue first, so a task that slipped past its deadline between runs
+	// is not announced as due soon
+	for _, kind := range []string{model.ReminderOverdue, model.ReminderDueSoon} {
+		for {
+			sent := 0
+			err := s.taskStore.ClaimReminders(ctx, kind, now, s.window, reminderBatchSize, func(tasks []model.Task) error {
+				sent = len(tasks)
+				return s.activityStore.LogActivities(ctx, reminderActivities(kind, tasks))
+			})
+			if err != nil {
+				s.logger.Warn("failed to send reminders", zap.String("kind", kind), zap.Error(err))
+				break
+			}
+			if sent > 0 {
+				s.logger.Info("sent reminders", zap.String("kind", kind), zap.Int("count", sent))
+			}
+			if sent < reminderBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// reminderActivities builds one activity entry per reminded task
+func reminderActivities(kind string, tasks []model.Task) []model.ActivityLog {
+	logs := make([]model.ActivityLog, len(tasks))
+	for i, t := range tasks {
+		details := fmt.Sprintf("Task '%s' is due at %s", t.Title, t.DueAt.Format(time.RFC3339))
+		if kind == model.ReminderOverdue {
+			details = fmt.Sprintf("Task '%s' is overdue (was due at %s)", t.Title, t.DueAt.Format(time.RFC3339))
+		}
+		logs[i] = model.ActivityLog{TenantID: t.TenantID, TaskID: t.ID, Action: kind, Details: details, Actor: auth.SystemActor}
+	}
+	return logs
+}
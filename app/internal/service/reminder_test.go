@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// Claiming a reminder is bookkeeping: the task keeps its version and
+// updated_at, and the reminder is sent once per due date
+func TestSendRemindersLeavesTasksAlone(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	scheduler := NewReminderScheduler(s.taskStore, s.activityStore, time.Minute, time.Hour, zap.NewNop())
+
+	now := time.Now()
+	due := now.Add(-time.Minute)
+	task, err := s.Create(ctx, model.TaskCreateRequest{Title: "late", DueAt: &due})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	scheduler.SendReminders(ctx, now)
+	scheduler.SendReminders(ctx, now)
+
+	got, err := s.taskStore.GetByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Version != task.Version || !got.UpdatedAt.Equal(task.UpdatedAt) {
+		t.Errorf("after reminders version = %d, updated_at = %v; want %d, %v",
+			got.Version, got.UpdatedAt, task.Version, task.UpdatedAt)
+	}
+
+	logs, err := s.GetActivities(ctx, task.ID, 10)
+	if err != nil {
+		t.Fatalf("GetActivities() error = %v", err)
+	}
+	sent := 0
+	for _, l := range logs {
+		if l.Action == model.ReminderOverdue {
+			sent++
+		}
+	}
+	if sent != 1 {
+		t.Errorf("sent %d overdue reminders, want 1", sent)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+// revertHistoryLimit caps how far back in a task's history a revert looks
+const revertHistoryLimit = 1000
+
+// Revert restores the title, description, status and priority a task had
+// right after the "created" or "updated" activity activityID. The current
+// values are rolled back through the recorded field changes of every later
+// update, and the result is written with Update, so the usual checks apply.
+// A non-zero expectedVersion makes the revert conditional.
+func (s *TaskService) Revert(ctx context.Context, id, activityID string, expectedVersion int) (*model.Task, error) {
+	current, err := s.taskStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get task: %w", err)
+	}
+	if expectedVersion != 0 && expectedVersion != current.Version {
+		return nil, ErrVersionConflict
+	}
+	// The rollback starts from these values, so they must still hold when writing
+	expectedVersion = current.Version
+
+	logs, err := s.activityStore.GetActivitiesByAction(ctx, id, []string{"created", "updated"}, revertHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("service: get task history: %w", err)
+	}
+
+	values := map[string]string{
+		"title":       current.Title,
+		"description": current.Description,
+		"status":      current.Status,
+		"priority":    current.Priority,
+	}
+	var source *model.ActivityLog
+	for i := range logs {
+		if logs[i].ID == activityID {
+			source = &logs[i]
+			break
+		}
+		if logs[i].Action != "updated" {
+			continue
+		}
+		if logs[i].Version == 0 {
+			// Logged before field changes were recorded
+			return nil, ErrHistoryIncomplete
+		}
+		for _, c := range logs[i].Changes {
+			if _, ok := values[c.Field]; ok {
+				values[c.Field] = stringOrEmpty(c.Before)
+			}
+		}
+	}
+	if source == nil {
+		return nil, ErrRevertTargetNotFound
+	}
+
+	req := model.TaskUpdateRequest{
+		Title:       revertedValue(current.Title, values["title"]),
+		Description: revertedValue(current.Description, values["description"]),
+		Status:      revertedValue(current.Status, values["status"]),
+		Priority:    revertedValue(current.Priority, values["priority"]),
+	}
+	if req.Title == nil && req.Description == nil && req.Status == nil && req.Priority == nil {
+		return current, nil
+	}
+
+	task, err := s.Update(ctx, id, req, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Creates logged before versions were recorded still produced version 1
+	version := source.Version
+	if source.Action == "created" {
+		version = 1
+	}
+	details := fmt.Sprintf("Task '%s' reverted to version %d", task.Title, version)
+	if version == 0 {
+		details = fmt.Sprintf("Task '%s' reverted to an earlier version", task.Title)
+	}
+	s.recordActivities(ctx, []model.ActivityLog{{TaskID: id, Action: "reverted", Details: details,
+		From: source.ID, Version: version}})
+
+	return task, nil
+}
+
+// revertedValue returns value if a revert changes the field from current,
+// and nil otherwise
+func revertedValue(current, value string) *string {
+	if value == current {
+		return nil
+	}
+	return &value
+}
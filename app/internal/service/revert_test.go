@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// revertedFields are the task fields a revert restores
+type revertedFields struct{ Title, Description, Status, Priority string }
+
+func TestRevert(t *testing.T) {
+	str := func(s string) *string { return &s }
+	updates := []model.TaskUpdateRequest{
+		{Title: str("v2 title")},
+		{Status: str("in_progress"), Priority: str("high")},
+		{Description: str("v4 description"), Title: str("v4 title")},
+	}
+
+	tests := []struct {
+		name    string
+		version int // version of the entry to revert to
+		want    revertedFields
+	}{
+		{"to creation", 1, revertedFields{"v1 title", "v1 description", "pending", "medium"}},
+		{"to the first update", 2, revertedFields{"v2 title", "v1 description", "pending", "medium"}},
+		{"to the second update", 3, revertedFields{"v2 title", "v1 description", "in_progress", "high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTaskService(t)
+			ctx := testContext(tenant.Default)
+			task, err := s.Create(ctx, model.TaskCreateRequest{Title: "v1 title", Description: "v1 description"})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			for _, req := range updates {
+				if _, err := s.Update(ctx, task.ID, req, 0); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+			}
+
+			target := historyEntry(t, ctx, s, task.ID, tt.version)
+			reverted, err := s.Revert(ctx, task.ID, target.ID, 4)
+			if err != nil {
+				t.Fatalf("Revert() error = %v", err)
+			}
+			got := revertedFields{reverted.Title, reverted.Description, reverted.Status, reverted.Priority}
+			if got != tt.want {
+				t.Errorf("Revert() = %+v, want %+v", got, tt.want)
+			}
+			if reverted.Version != 5 {
+				t.Errorf("Revert() version = %d, want 5", reverted.Version)
+			}
+
+			history, err := s.History(ctx, task.ID, 1)
+			if err != nil {
+				t.Fatalf("History() error = %v", err)
+			}
+			if len(history) != 1 || history[0].Action != "reverted" || history[0].From != target.ID ||
+				history[0].Version != tt.version {
+				t.Errorf("newest history entry = %+v, want the revert to version %d", history, tt.version)
+			}
+		})
+	}
+}
+
+// An update must be in the history as soon as it returns, or a revert right
+// after it would roll back from stale values
+func TestRevertRightAfterUpdate(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, s, "original")
+	created := historyEntry(t, ctx, s, task.ID, 1)
+
+	for i := 0; i < 20; i++ {
+		title := "changed"
+		current, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Title: &title}, 0)
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		reverted, err := s.Revert(ctx, task.ID, created.ID, current.Version)
+		if err != nil {
+			t.Fatalf("Revert() error = %v", err)
+		}
+		if reverted.Title != "original" {
+			t.Fatalf("Revert() title = %q after %d rounds, want %q", reverted.Title, i+1, "original")
+		}
+	}
+}
+
+func TestRevertErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, ctx context.Context, s *TaskService, id string) (activityID string, version int)
+		err   error
+	}{
+		{
+			name: "stale expected version",
+			setup: func(t *testing.T, ctx context.Context, s *TaskService, id string) (string, int) {
+				return historyEntry(t, ctx, s, id, 1).ID, 1
+			},
+			err: ErrVersionConflict,
+		},
+		{
+			name: "unknown activity",
+			setup: func(t *testing.T, ctx context.Context, s *TaskService, id string) (string, int) {
+				return "no-such-activity", 0
+			},
+			err: ErrRevertTargetNotFound,
+		},
+		{
+			name: "activity of another task",
+			setup: func(t *testing.T, ctx context.Context, s *TaskService, id string) (string, int) {
+				other := createTask(t, ctx, s, "other")
+				return historyEntry(t, ctx, s, other.ID, 1).ID, 0
+			},
+			err: ErrRevertTargetNotFound,
+		},
+		{
+			name: "update logged before versions were recorded",
+			setup: func(t *testing.T, ctx context.Context, s *TaskService, id string) (string, int) {
+				target := historyEntry(t, ctx, s, id, 1)
+				legacy := model.ActivityLog{TenantID: tenant.Default, TaskID: id, Action: "updated",
+					Details: "Task 'task' updated", Timestamp: time.Now()}
+				if err := s.activityStore.LogActivities(ctx, []model.ActivityLog{legacy}); err != nil {
+					t.Fatalf("LogActivities() error = %v", err)
+				}
+				return target.ID, 0
+			},
+			err: ErrHistoryIncomplete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTaskService(t)
+			ctx := testContext(tenant.Default)
+			task := createTask(t, ctx, s, "task")
+			title := "renamed"
+			if _, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Title: &title}, 0); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			activityID, version := tt.setup(t, ctx, s, task.ID)
+			if _, err := s.Revert(ctx, task.ID, activityID, version); !errors.Is(err, tt.err) {
+				t.Fatalf("Revert() error = %v, want %v", err, tt.err)
+			}
+			if current, _ := s.taskStore.GetByID(ctx, task.ID); current.Title != title {
+				t.Errorf("failed revert changed the title to %q", current.Title)
+			}
+		})
+	}
+}
+
+// historyEntry returns the "created" or "updated" entry of a task that
+// produced version. Creates are logged asynchronously, so it waits briefly.
+func historyEntry(t *testing.T, ctx context.Context, s *TaskService, taskID string, version int) model.ActivityLog {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		logs, err := s.History(ctx, taskID, 100)
+		if err != nil {
+			t.Fatalf("History() error = %v", err)
+		}
+		for _, l := range logs {
+			if (l.Action == "created" || l.Action == "updated") && l.Version == version {
+				return l
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no history entry for version %d of task %s in %+v", version, taskID, logs)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -2,56 +2,86 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
+	"github.com/hamfa/task-manager/internal/auth"
 	"github.com/hamfa/task-manager/internal/model"
 	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
 )
 
 // TaskService handles business logic for tasks
 type TaskService struct {
-	postgresRepo *repository.PostgresRepository
-	mongoRepo    *repository.MongoRepository
-	redisCache   *repository.RedisCache
-	logger       *zap.Logger
+	taskStore       repository.TaskStore
+	userStore       repository.UserStore
+	projectStore    repository.ProjectStore
+	dependencyStore repository.DependencyStore
+	activityStore   repository.ActivityStore
+	blobStore       repository.BlobStore
+	taskCache       repository.TaskCache
+	summaryCache    repository.ProjectSummaryCache
+	workflow        model.Workflow
+	subtasks        model.SubtaskRules
+	logger          *zap.Logger
 }
 
 // NewTaskService creates a new task service
 func NewTaskService(
-	pg *repository.PostgresRepository,
-	mongo *repository.MongoRepository,
-	redis *repository.RedisCache,
+	tasks repository.TaskStore,
+	users repository.UserStore,
+	projects repository.ProjectStore,
+	dependencies repository.DependencyStore,
+	activities repository.ActivityStore,
+	blobs repository.BlobStore,
+	cache repository.TaskCache,
+	summaries repository.ProjectSummaryCache,
+	workflow model.Workflow,
+	subtasks model.SubtaskRules,
 	logger *zap.Logger,
 ) *TaskService {
 	return &TaskService{
-		postgresRepo: pg,
-		mongoRepo:    mongo,
-		redisCache:   redis,
-		logger:       logger,
+		taskStore:       tasks,
+		userStore:       users,
+		projectStore:    projects,
+		dependencyStore: dependencies,
+		activityStore:   activities,
+		blobStore:       blobs,
+		taskCache:       cache,
+		summaryCache:    summaries,
+		workflow:        workflow,
+		subtasks:        subtasks,
+		logger:          logger,
 	}
 }
 
 // Create creates a new task and logs the activity
 func (s *TaskService) Create(ctx context.Context, req model.TaskCreateRequest) (*model.Task, error) {
-	task, err := s.postgresRepo.Create(ctx, req)
+	if err := s.checkCreate(ctx, &req); err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskStore.Create(ctx, req)
+	if errors.Is(err, ErrInvalidParent) {
+		// The store explains what is wrong with the parent to the client
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("service: create task: %w", err)
 	}
 
 	// Cache the new task
-	if cacheErr := s.redisCache.SetTask(ctx, task); cacheErr != nil {
+	if cacheErr := s.taskCache.SetTask(ctx, task); cacheErr != nil {
 		s.logger.Warn("failed to cache new task", zap.Error(cacheErr))
 	}
+	s.invalidateSummaries(ctx, task)
 
-	// Log activity to MongoDB (non-blocking)
-	go func() {
-		if logErr := s.mongoRepo.LogActivity(context.Background(), task.ID, "created",
-			fmt.Sprintf("Task '%s' created with priority %s", task.Title, task.Priority)); logErr != nil {
-			s.logger.Warn("failed to log activity", zap.Error(logErr))
-		}
-	}()
+	// Log activity (non-blocking)
+	s.logActivities(ctx, []model.ActivityLog{{TaskID: task.ID, Action: "created", Version: task.Version,
+		Details: fmt.Sprintf("Task '%s' created with priority %s", task.Title, task.Priority)}})
 
 	return task, nil
 }
@@ -59,104 +89,459 @@ func (s *TaskService) Create(ctx context.Context, req model.TaskCreateRequest) (
 // GetByID retrieves a task by ID with cache-aside pattern
 func (s *TaskService) GetByID(ctx context.Context, id string) (*model.Task, error) {
 	// Try cache first
-	cached, err := s.redisCache.GetTask(ctx, id)
+	cached, err := s.taskCache.GetTask(ctx, id)
 	if err != nil {
 		s.logger.Warn("cache lookup failed", zap.Error(err))
 	}
-	if cached != nil {
+	if cached != nil && cached.DeletedAt == nil {
 		s.logger.Debug("cache hit", zap.String("task_id", id))
 		return cached, nil
 	}
 
-	// Cache miss — fetch from PostgreSQL
-	task, err := s.postgresRepo.GetByID(ctx, id)
+	// Cache miss — fetch from the task store
+	task, err := s.taskStore.GetByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("service: get task: %w", err)
 	}
 
 	// Populate cache
-	if cacheErr := s.redisCache.SetTask(ctx, task); cacheErr != nil {
+	if cacheErr := s.taskCache.SetTask(ctx, task); cacheErr != nil {
 		s.logger.Warn("failed to cache task", zap.Error(cacheErr))
 	}
 
 	return task, nil
 }
 
-// List retrieves paginated tasks
-func (s *TaskService) List(ctx context.Context, page, perPage int, status string) (*model.TaskListResponse, error) {
-	tasks, total, err := s.postgresRepo.List(ctx, page, perPage, status)
+// List retrieves one page of tasks, using keyset pagination when a cursor is given
+func (s *TaskService) List(ctx context.Context, q model.TaskListQuery) (*model.TaskListResponse, error) {
+	q.Normalize()
+
+	page, err := s.taskStore.List(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("service: list tasks: %w", err)
 	}
 
+	tasks := page.Tasks
 	if tasks == nil {
 		tasks = []model.Task{}
 	}
 
-	return &model.TaskListResponse{
+	resp := &model.TaskListResponse{
 		Data:    tasks,
-		Total:   total,
-		Page:    page,
-		PerPage: perPage,
-	}, nil
+		Total:   page.Total,
+		PerPage: q.PerPage,
+	}
+	if q.Cursor == nil {
+		resp.Page = q.Page
+	}
+
+	if len(tasks) == 0 {
+		return resp, nil
+	}
+
+	// A backward scan reports HasMore towards the start of the list
+	backward := q.Cursor != nil && q.Cursor.Backward
+	hasNext := page.HasMore
+	hasPrev := q.Cursor != nil || q.Page > 1
+	if backward {
+		hasNext, hasPrev = true, page.HasMore
+	}
+
+	if hasNext {
+		resp.NextCursor = q.CursorFor(tasks[len(tasks)-1], false).Encode()
+	}
+	if hasPrev {
+		resp.PrevCursor = q.CursorFor(tasks[0], true).Encode()
+	}
+
+	return resp, nil
+}
+
+// Update modifies a task and invalidates its cache. A non-zero expectedVersion
+// makes the update conditional (optimistic concurrency). Status changes must
+// follow the workflow, due_at may not precede start_at and user references
+// must exist. A task with open blockers cannot be started or completed and,
+// depending on the subtask rules, one with open subtasks cannot be completed.
+// The task is locked and re-read so that the checks and the change history
+// see its current values.
+func (s *TaskService) Update(ctx context.Context, id string, req model.TaskUpdateRequest, expectedVersion int) (*model.Task, error) {
+	open, err := s.countOpenWork(ctx, id, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev model.Task
+	task, err := s.taskStore.Modify(ctx, id, expectedVersion, s.guardUpdate(ctx, &prev, open, func(t *model.Task) error {
+		req.ApplyTo(t)
+		return nil
+	}))
+	if err != nil {
+		// Guard errors are client-facing; the store already adds context to its own
+		return nil, err
+	}
+
+	s.recacheTask(ctx, task)
+	s.invalidateSummaries(ctx, task, &prev)
+	s.recordActivities(ctx, updateActivities(task, &prev, fmt.Sprintf("Task '%s' updated", task.Title)))
+
+	return task, nil
 }
 
-// Update modifies a task and invalidates its cache
-func (s *TaskService) Update(ctx context.Context, id string, req model.TaskUpdateRequest) (*model.Task, error) {
-	task, err := s.postgresRepo.Update(ctx, id, req)
+// Patch atomically applies a merge patch or JSON patch document to a task.
+// A non-zero expectedVersion makes the patch conditional.
+func (s *TaskService) Patch(ctx context.Context, id, contentType string, patch []byte, expectedVersion int) (*model.Task, error) {
+	// Any patch may change the status
+	open, err := s.countOpenWork(ctx, id, anyStatus)
 	if err != nil {
-		return nil, fmt.Errorf("service: update task: %w", err)
+		return nil, err
 	}
 
-	// Invalidate and recache
-	if cacheErr := s.redisCache.InvalidateTask(ctx, id); cacheErr != nil {
+	var prev model.Task
+	task, err := s.taskStore.Modify(ctx, id, expectedVersion, s.guardUpdate(ctx, &prev, open, func(t *model.Task) error {
+		return applyTaskPatch(t, contentType, patch)
+	}))
+	if err != nil {
+		// Patch errors are client-facing; the store already adds context to its own
+		return nil, err
+	}
+
+	s.recacheTask(ctx, task)
+	s.invalidateSummaries(ctx, task, &prev)
+	s.recordActivities(ctx, updateActivities(task, &prev, fmt.Sprintf("Task '%s' patched", task.Title)))
+
+	return task, nil
+}
+
+// recacheTask replaces the cached copy of a task after a write
+func (s *TaskService) recacheTask(ctx context.Context, task *model.Task) {
+	if cacheErr := s.taskCache.InvalidateTask(ctx, task.ID); cacheErr != nil {
 		s.logger.Warn("failed to invalidate cache", zap.Error(cacheErr))
 	}
-	if cacheErr := s.redisCache.SetTask(ctx, task); cacheErr != nil {
+	if cacheErr := s.taskCache.SetTask(ctx, task); cacheErr != nil {
 		s.logger.Warn("failed to recache task", zap.Error(cacheErr))
 	}
+}
+
+// invalidateSummaries drops the cached summaries of the projects the given
+// task versions belong to
+func (s *TaskService) invalidateSummaries(ctx context.Context, tasks ...*model.Task) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, t := range tasks {
+		if t == nil || t.ProjectID == nil || seen[*t.ProjectID] {
+			continue
+		}
+		seen[*t.ProjectID] = true
+		ids = append(ids, *t.ProjectID)
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	if cacheErr := s.summaryCache.InvalidateProjectSummaries(ctx, ids); cacheErr != nil {
+		s.logger.Warn("failed to invalidate project summaries", zap.Error(cacheErr))
+	}
+}
+
+// logActivities writes activity entries in the background, see stampActivities
+func (s *TaskService) logActivities(ctx context.Context, logs []model.ActivityLog) {
+	s.stampActivities(ctx, logs)
 
-	// Log activity
 	go func() {
-		if logErr := s.mongoRepo.LogActivity(context.Background(), id, "updated",
-			fmt.Sprintf("Task '%s' updated", task.Title)); logErr != nil {
-			s.logger.Warn("failed to log activity", zap.Error(logErr))
+		if logErr := s.activityStore.LogActivities(context.Background(), logs); logErr != nil {
+			s.logger.Warn("failed to log activities", zap.Error(logErr))
 		}
 	}()
+}
 
-	return task, nil
+// recordActivities writes activity entries before returning. Updates use it
+// because a revert rolls back through the field changes they record, which
+// must not lag behind the task. A failed write is only logged, as the change
+// it records has already been made.
+func (s *TaskService) recordActivities(ctx context.Context, logs []model.ActivityLog) {
+	s.stampActivities(ctx, logs)
+
+	if logErr := s.activityStore.LogActivities(context.WithoutCancel(ctx), logs); logErr != nil {
+		s.logger.Warn("failed to log activities", zap.Error(logErr))
+	}
+}
+
+// stampActivities records the authenticated subject of ctx as the actor of
+// activity entries. Entries without a tenant get the tenant of ctx; they are
+// timestamped now, so that entries keep the order they were logged in even
+// though the writes may not.
+func (s *TaskService) stampActivities(ctx context.Context, logs []model.ActivityLog) {
+	actor := auth.Actor(ctx)
+	tenantID, _ := tenant.FromContext(ctx)
+	now := time.Now()
+	for i := range logs {
+		if actor != "" {
+			logs[i].Actor = actor
+		}
+		if logs[i].TenantID == "" {
+			logs[i].TenantID = tenantID
+		}
+		if logs[i].Timestamp.IsZero() {
+			logs[i].Timestamp = now
+		}
+	}
 }
 
-// Delete removes a task
-func (s *TaskService) Delete(ctx context.Context, id string) error {
+// Delete moves a task to the trash. A non-zero expectedVersion makes the delete
+// conditional. Its subtasks are rejected, trashed or detached according to the
+// subtask rules. Attachments stay until the task is purged.
+func (s *TaskService) Delete(ctx context.Context, id string, expectedVersion int) error {
 	// Get task info before delete for logging
-	task, _ := s.postgresRepo.GetByID(ctx, id)
+	task, _ := s.taskStore.GetByID(ctx, id)
+	if task == nil {
+		task = &model.Task{ID: id, Title: id}
+	}
 
-	if err := s.postgresRepo.Delete(ctx, id); err != nil {
+	subtasks, err := s.taskStore.Delete(ctx, id, expectedVersion, s.subtasks.OnDelete)
+	if err != nil {
 		return fmt.Errorf("service: delete task: %w", err)
 	}
 
 	// Invalidate cache
-	if cacheErr := s.redisCache.InvalidateTask(ctx, id); cacheErr != nil {
+	ids := []string{id}
+	changed := []*model.Task{task}
+	for i := range subtasks {
+		ids = append(ids, subtasks[i].ID)
+		changed = append(changed, &subtasks[i])
+	}
+	if cacheErr := s.taskCache.InvalidateTasks(ctx, ids); cacheErr != nil {
 		s.logger.Warn("failed to invalidate cache", zap.Error(cacheErr))
 	}
+	s.invalidateSummaries(ctx, changed...)
 
 	// Log activity
+	logs := []model.ActivityLog{{TaskID: id, Action: "deleted",
+		Details: fmt.Sprintf("Task '%s' moved to trash", task.Title)}}
+	s.logActivities(ctx, append(logs, subtaskActivities(task, subtasks, s.subtasks.OnDelete)...))
+
+	return nil
+}
+
+// ListTrash retrieves one page of the tasks in the trash
+func (s *TaskService) ListTrash(ctx context.Context, q model.TaskListQuery) (*model.TaskListResponse, error) {
+	q.Filter.Trashed = true
+	return s.List(ctx, q)
+}
+
+// Restore takes a task out of the trash together with the subtasks that were
+// trashed with it
+func (s *TaskService) Restore(ctx context.Context, id string) (*model.Task, error) {
+	task, subtasks, err := s.taskStore.Restore(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: restore task: %w", err)
+	}
+
+	ids := []string{id}
+	changed := []*model.Task{task}
+	for i := range subtasks {
+		ids = append(ids, subtasks[i].ID)
+		changed = append(changed, &subtasks[i])
+	}
+	if cacheErr := s.taskCache.InvalidateTasks(ctx, ids); cacheErr != nil {
+		s.logger.Warn("failed to invalidate cache", zap.Error(cacheErr))
+	}
+	s.invalidateSummaries(ctx, changed...)
+
+	logs := []model.ActivityLog{{TaskID: id, Action: "restored",
+		Details: fmt.Sprintf("Task '%s' restored from the trash", task.Title)}}
+	for _, t := range subtasks {
+		logs = append(logs, model.ActivityLog{TaskID: t.ID, Action: "restored",
+			Details: fmt.Sprintf("Task '%s' restored with its parent '%s'", t.Title, task.Title)})
+	}
+	s.logActivities(ctx, logs)
+
+	return task, nil
+}
+
+// Purge permanently deletes a task in the trash, its trashed subtasks and
+// their attachments
+func (s *TaskService) Purge(ctx context.Context, id string) error {
+	task, subtasks, err := s.taskStore.Purge(ctx, id)
+	if err != nil {
+		return fmt.Errorf("service: purge task: %w", err)
+	}
+
+	purged := append([]model.Task{*task}, subtasks...)
+	ids := make([]string, 0, len(purged))
+	for _, t := range purged {
+		ids = append(ids, t.ID)
+	}
+	s.removeAttachments(ctx, ids)
+	s.logActivities(ctx, purgedActivities(purged))
+
+	return nil
+}
+
+// removeAttachments deletes the attachment contents of purged tasks in the
+// background; their metadata was deleted with the tasks
+func (s *TaskService) removeAttachments(ctx context.Context, taskIDs []string) {
+	tenantID, _ := tenant.FromContext(ctx)
+
 	go func() {
-		title := id
-		if task != nil {
-			title = task.Title
-		}
-		if logErr := s.mongoRepo.LogActivity(context.Background(), id, "deleted",
-			fmt.Sprintf("Task '%s' deleted", title)); logErr != nil {
-			s.logger.Warn("failed to log activity", zap.Error(logErr))
+		for _, id := range taskIDs {
+			removeAttachmentBlobs(context.Background(), s.blobStore, s.logger, tenantID, id)
 		}
 	}()
+}
+
+// ListChildren retrieves one page of the direct subtasks of a task, each with
+// the rollup of its own subtasks
+func (s *TaskService) ListChildren(ctx context.Context, id string, q model.TaskListQuery) (*model.TaskListResponse, error) {
+	if _, err := s.taskStore.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("service: get task: %w", err)
+	}
+
+	q.Filter.ParentIDs = []string{id}
+	resp, err := s.List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return resp, nil
+	}
+
+	ids := make([]string, len(resp.Data))
+	for i, t := range resp.Data {
+		ids[i] = t.ID
+	}
+	progress, err := s.taskStore.SubtaskProgress(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service: count subtasks: %w", err)
+	}
+	for i, t := range resp.Data {
+		if p, ok := progress[t.ID]; ok {
+			resp.Data[i].Progress = &p
+		}
+	}
 
+	return resp, nil
+}
+
+// Tree returns a task with its subtasks nested below it, down to
+// model.MaxTaskDepth levels. Every task with subtasks carries the rollup of
+// all of them.
+func (s *TaskService) Tree(ctx context.Context, id string) (*model.TaskNode, error) {
+	tasks, err := s.taskStore.Subtree(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get task tree: %w", err)
+	}
+
+	// Tasks come level by level, so walking them backwards finishes every
+	// subtask before its parent
+	children := make(map[string][]model.TaskNode)
+	var root model.TaskNode
+	for i := len(tasks) - 1; i >= 0; i-- {
+		node := model.TaskNode{Task: tasks[i], Children: children[tasks[i].ID]}
+		if node.Children == nil {
+			node.Children = []model.TaskNode{}
+		}
+
+		var progress model.TaskProgress
+		for j, k := 0, len(node.Children)-1; j < k; j, k = j+1, k-1 {
+			node.Children[j], node.Children[k] = node.Children[k], node.Children[j]
+		}
+		for _, child := range node.Children {
+			progress.Add(child.Status)
+			if child.Progress != nil {
+				progress.Merge(*child.Progress)
+			}
+		}
+		if progress.Subtasks > 0 {
+			node.Progress = &progress
+		}
+
+		if i == 0 {
+			root = node
+		} else {
+			children[*node.ParentID] = append(children[*node.ParentID], node)
+		}
+	}
+
+	return &root, nil
+}
+
+// FlushCache drops every cached task
+func (s *TaskService) FlushCache(ctx context.Context) error {
+	if err := s.taskCache.InvalidateAll(ctx); err != nil {
+		return fmt.Errorf("service: flush cache: %w", err)
+	}
+	s.logger.Info("task cache flushed", zap.String("actor", auth.Actor(ctx)))
 	return nil
 }
 
+// ReleaseUser unassigns a user from the tasks of the tenant and clears them
+// as reporter, before the user is deleted
+func (s *TaskService) ReleaseUser(ctx context.Context, userID string) error {
+	released, err := s.taskStore.ReleaseUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("service: release user: %w", err)
+	}
+	if len(released) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(released))
+	var logs []model.ActivityLog
+	for i, r := range released {
+		ids[i] = r.Task.ID
+		if r.WasAssignee {
+			logs = append(logs, reassignedActivity(&released[i].Task, &userID, nil))
+		}
+	}
+
+	if cacheErr := s.taskCache.InvalidateTasks(ctx, ids); cacheErr != nil {
+		s.logger.Warn("failed to invalidate cache", zap.Error(cacheErr))
+	}
+	s.logActivities(ctx, logs)
+
+	return nil
+}
+
+// TagCounts returns how many tasks carry each tag, most used first
+func (s *TaskService) TagCounts(ctx context.Context) ([]model.TagCount, error) {
+	counts, err := s.taskStore.TagCounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("service: count tags: %w", err)
+	}
+	if counts == nil {
+		counts = []model.TagCount{}
+	}
+	return counts, nil
+}
+
 // GetActivities returns activity logs for a task
 func (s *TaskService) GetActivities(ctx context.Context, taskID string, limit int64) ([]model.ActivityLog, error) {
-	return s.mongoRepo.GetActivities(ctx, taskID, limit)
+	return s.activityStore.GetActivities(ctx, taskID, limit)
+}
+
+// History retrieves the change history of a task, newest first, with a
+// unified diff for every description change
+func (s *TaskService) History(ctx context.Context, id string, limit int64) ([]model.ActivityLog, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	logs, err := s.activityStore.GetActivitiesByAction(ctx, id, model.HistoryActions, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: get task history: %w", err)
+	}
+	// Build new changes rather than filling in the store's
+	for i := range logs {
+		changes := make([]model.FieldChange, len(logs[i].Changes))
+		for j, c := range logs[i].Changes {
+			if c.Field == "description" {
+				c.Diff = unifiedDiff(stringOrEmpty(c.Before), stringOrEmpty(c.After))
+			}
+			changes[j] = c
+		}
+		if len(changes) > 0 {
+			logs[i].Changes = changes
+		}
+	}
+	return logs, nil
 }
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// newTestTaskService wires a TaskService to fresh in-memory stores, the way
+// cmd/server does for STORAGE_BACKEND=memory
+func newTestTaskService(t *testing.T) *TaskService {
+	t.Helper()
+
+	blobs, err := repository.NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore() error = %v", err)
+	}
+	tasks := repository.NewMemoryTaskStore()
+	cache := repository.NewMemoryCache()
+	return NewTaskService(
+		tasks,
+		repository.NewMemoryUserStore(),
+		repository.NewMemoryProjectStore(tasks),
+		repository.NewMemoryDependencyStore(tasks),
+		repository.NewMemoryActivityStore(),
+		blobs,
+		cache,
+		cache,
+		model.DefaultWorkflow(),
+		model.SubtaskRules{OnDelete: model.SubtasksReject},
+		zap.NewNop(),
+	)
+}
+
+// testContext returns a context scoped to tenantID
+func testContext(tenantID string) context.Context {
+	return tenant.WithID(context.Background(), tenantID)
+}
+
+// createTask creates a task with the given title or fails the test
+func createTask(t *testing.T, ctx context.Context, s *TaskService, title string) *model.Task {
+	t.Helper()
+
+	task, err := s.Create(ctx, model.TaskCreateRequest{Title: title})
+	if err != nil {
+		t.Fatalf("Create(%q) error = %v", title, err)
+	}
+	return task
+}
+
+func TestListCursorPaging(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+
+	var titles []string
+	for i := 0; i < 7; i++ {
+		title := fmt.Sprintf("task %d", i)
+		createTask(t, ctx, s, title)
+		titles = append(titles, title)
+	}
+
+	list := func(cursor string) *model.TaskListResponse {
+		t.Helper()
+		q := model.TaskListQuery{PerPage: 3, Sort: model.SortTitle, Order: model.OrderAsc}
+		if cursor != "" {
+			c, err := model.DecodeTaskCursor(cursor)
+			if err != nil {
+				t.Fatalf("DecodeTaskCursor() error = %v", err)
+			}
+			q.Cursor = c
+		}
+		resp, err := s.List(ctx, q)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		return resp
+	}
+
+	pages := []struct {
+		titles  []string
+		hasNext bool
+		hasPrev bool
+	}{
+		{titles[0:3], true, false},
+		{titles[3:6], true, true},
+		{titles[6:7], false, true},
+	}
+
+	// Forward through next_cursor, remembering each page's prev_cursor
+	var prevCursors []string
+	cursor := ""
+	for i, want := range pages {
+		resp := list(cursor)
+		checkPage(t, fmt.Sprintf("forward page %d", i+1), resp, want.titles, want.hasNext, want.hasPrev)
+		prevCursors = append(prevCursors, resp.PrevCursor)
+		cursor = resp.NextCursor
+	}
+
+	// Backward from the last page: a previous page always has a next page
+	for i := len(pages) - 1; i > 0; i-- {
+		want := pages[i-1]
+		resp := list(prevCursors[i])
+		checkPage(t, fmt.Sprintf("backward page %d", i), resp, want.titles, true, want.hasPrev)
+	}
+}
+
+func checkPage(t *testing.T, name string, resp *model.TaskListResponse, titles []string, hasNext, hasPrev bool) {
+	t.Helper()
+
+	var got []string
+	for _, task := range resp.Data {
+		got = append(got, task.Title)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(titles) {
+		t.Errorf("%s: titles = %v, want %v", name, got, titles)
+	}
+	if (resp.NextCursor != "") != hasNext {
+		t.Errorf("%s: next_cursor = %q, want present=%v", name, resp.NextCursor, hasNext)
+	}
+	if (resp.PrevCursor != "") != hasPrev {
+		t.Errorf("%s: prev_cursor = %q, want present=%v", name, resp.PrevCursor, hasPrev)
+	}
+}
+
+func TestUpdateFollowsWorkflow(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, s, "workflow")
+
+	steps := []struct {
+		to string
+		ok bool
+	}{
+		{"completed", false},
+		{"in_progress", true},
+		{"completed", true},
+		{"cancelled", false},
+		{"in_progress", true},
+	}
+	for _, step := range steps {
+		status := step.to
+		_, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Status: &status}, 0)
+		if step.ok && err != nil {
+			t.Fatalf("Update(status=%s) error = %v", status, err)
+		}
+		var transition *TransitionError
+		if !step.ok && !errors.As(err, &transition) {
+			t.Fatalf("Update(status=%s) error = %v, want a TransitionError", status, err)
+		}
+	}
+}
+
+func TestConditionalWrites(t *testing.T) {
+	title := "changed"
+	tests := []struct {
+		name  string
+		write func(s *TaskService, ctx context.Context, id string, version int) error
+	}{
+		{"update", func(s *TaskService, ctx context.Context, id string, version int) error {
+			_, err := s.Update(ctx, id, model.TaskUpdateRequest{Title: &title}, version)
+			return err
+		}},
+		{"patch", func(s *TaskService, ctx context.Context, id string, version int) error {
+			_, err := s.Patch(ctx, id, model.MergePatchContentType, []byte(`{"title":"changed"}`), version)
+			return err
+		}},
+		{"delete", func(s *TaskService, ctx context.Context, id string, version int) error {
+			return s.Delete(ctx, id, version)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTaskService(t)
+			ctx := testContext(tenant.Default)
+			task := createTask(t, ctx, s, "original")
+			other := "concurrent"
+			current, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Title: &other}, task.Version)
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			if err := tt.write(s, ctx, task.ID, task.Version); !errors.Is(err, ErrVersionConflict) {
+				t.Fatalf("%s with a stale version error = %v, want ErrVersionConflict", tt.name, err)
+			}
+			got, err := s.GetByID(ctx, task.ID)
+			if err != nil {
+				t.Fatalf("GetByID() error = %v", err)
+			}
+			if got.Title != other || got.Version != current.Version {
+				t.Fatalf("stale %s changed the task to %q, version %d", tt.name, got.Title, got.Version)
+			}
+
+			if err := tt.write(s, ctx, task.ID, current.Version); err != nil {
+				t.Fatalf("%s with the current version error = %v", tt.name, err)
+			}
+		})
+	}
+}
+
+// Writes replace the cached copy, so a GET right after one sees the new version
+func TestGetByIDAfterWriteSeesNewVersion(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, s, "cached")
+	if _, err := s.GetByID(ctx, task.ID); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	title := "renamed"
+	updated, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Title: &title}, 0)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := s.GetByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if got.Version != updated.Version || got.Version != task.Version+1 {
+		t.Errorf("GetByID() version = %d, want %d", got.Version, task.Version+1)
+	}
+}
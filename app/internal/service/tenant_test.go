@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	s := newTestTaskService(t)
+	acme, globex := testContext("acme"), testContext("globex")
+
+	task := createTask(t, acme, s, "acme launch plan")
+	title := "acme launch plan, revised"
+	if _, err := s.Update(acme, task.ID, model.TaskUpdateRequest{Title: &title}, 0); err != nil {
+		t.Fatalf("Update() in own tenant error = %v", err)
+	}
+	other := createTask(t, globex, s, "globex launch plan")
+
+	notFound := []struct {
+		name string
+		call func(ctx context.Context) error
+	}{
+		{"get", func(ctx context.Context) error {
+			_, err := s.GetByID(ctx, task.ID)
+			return err
+		}},
+		{"update", func(ctx context.Context) error {
+			status := "in_progress"
+			_, err := s.Update(ctx, task.ID, model.TaskUpdateRequest{Status: &status}, 0)
+			return err
+		}},
+		{"revert", func(ctx context.Context) error {
+			_, err := s.Revert(ctx, task.ID, "any", 0)
+			return err
+		}},
+		{"delete", func(ctx context.Context) error {
+			return s.Delete(ctx, task.ID, 0)
+		}},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name+" from another tenant", func(t *testing.T) {
+			if err := tt.call(globex); !errors.Is(err, repository.ErrTaskNotFound) {
+				t.Fatalf("error = %v, want ErrTaskNotFound", err)
+			}
+		})
+	}
+
+	t.Run("list and search", func(t *testing.T) {
+		for _, q := range []model.TaskListQuery{{}, {Filter: model.TaskFilter{Search: "launch"}}} {
+			resp, err := s.List(globex, q)
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(resp.Data) != 1 || resp.Data[0].ID != other.ID {
+				t.Fatalf("List(%+v) in globex = %+v, want only its own task", q.Filter, resp.Data)
+			}
+		}
+	})
+
+	t.Run("activities", func(t *testing.T) {
+		logs, err := s.GetActivities(globex, task.ID, 50)
+		if err != nil {
+			t.Fatalf("GetActivities() error = %v", err)
+		}
+		if len(logs) != 0 {
+			t.Fatalf("GetActivities() from globex = %+v, want none", logs)
+		}
+		if logs, _ := s.GetActivities(acme, task.ID, 50); len(logs) == 0 {
+			t.Fatal("GetActivities() from acme returned nothing")
+		}
+	})
+
+	t.Run("blocker from another tenant", func(t *testing.T) {
+		if _, err := s.AddBlocker(globex, other.ID, task.ID); !errors.Is(err, repository.ErrInvalidDependency) {
+			t.Fatalf("AddBlocker() error = %v, want ErrInvalidDependency", err)
+		}
+	})
+
+	if got, err := s.GetByID(acme, task.ID); err != nil || got.Title != title {
+		t.Fatalf("GetByID() in own tenant = %+v, %v", got, err)
+	}
+}
+
+func TestNoTenantFailsClosed(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := context.Background()
+
+	if _, err := s.Create(ctx, model.TaskCreateRequest{Title: "orphan"}); !errors.Is(err, repository.ErrNoTenant) {
+		t.Errorf("Create() error = %v, want ErrNoTenant", err)
+	}
+	if _, err := s.List(ctx, model.TaskListQuery{}); !errors.Is(err, repository.ErrNoTenant) {
+		t.Errorf("List() error = %v, want ErrNoTenant", err)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/auth"
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// trashPurgeBatchSize caps how many tasks one purge pass deletes
+const trashPurgeBatchSize = 100
+
+// TrashPurger periodically purges the tasks that have been in the trash for
+// longer than the retention period, along with their attachments
+type TrashPurger struct {
+	taskStore     repository.TaskStore
+	activityStore repository.ActivityStore
+	blobStore     repository.BlobStore
+	retention     time.Duration
+	interval      time.Duration
+	logger        *zap.Logger
+}
+
+// NewTrashPurger creates a purger that runs every interval and purges tasks
+// trashed more than retention ago
+func NewTrashPurger(
+	tasks repository.TaskStore,
+	activities repository.ActivityStore,
+	blobs repository.BlobStore,
+	retention, interval time.Duration,
+	logger *zap.Logger,
+) *TrashPurger {
+	return &TrashPurger{
+		taskStore:     tasks,
+		activityStore: activities,
+		blobStore:     blobs,
+		retention:     retention,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// Run purges expired tasks until ctx is cancelled
+func (p *TrashPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeExpired(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired purges every task trashed before now minus the retention
+// period, in every tenant
+func (p *TrashPurger) PurgeExpired(ctx context.Context, now time.Time) {
+	ctx = tenant.WithID(ctx, tenant.All)
+	cutoff := now.Add(-p.retention)
+
+	for {
+		tasks, err := p.taskStore.PurgeTrash(ctx, cutoff, trashPurgeBatchSize)
+		if err != nil {
+			p.logger.Warn("failed to purge trash", zap.Error(err))
+			return
+		}
+		if len(tasks) == 0 {
+			return
+		}
+
+		for _, t := range tasks {
+			removeAttachmentBlobs(ctx, p.blobStore, p.logger, t.TenantID, t.ID)
+		}
+		logs := purgedActivities(tasks)
+		for i := range logs {
+			logs[i].Actor = auth.SystemActor
+		}
+		if err := p.activityStore.LogActivities(ctx, logs); err != nil {
+			p.logger.Warn("failed to log activities", zap.Error(err))
+		}
+		p.logger.Info("purged trash", zap.Int("count", len(tasks)))
+
+		if len(tasks) < trashPurgeBatchSize {
+			return
+		}
+	}
+}
+
+// purgedActivities builds one activity entry per purged task
+func purgedActivities(tasks []model.Task) []model.ActivityLog {
+	logs := make([]model.ActivityLog, len(tasks))
+	for i, t := range tasks {
+		logs[i] = model.ActivityLog{TenantID: t.TenantID, TaskID: t.ID, Action: "purged",
+			Details: fmt.Sprintf("Task '%s' permanently deleted from the trash", t.Title)}
+	}
+	return logs
+}
+
+// removeAttachmentBlobs deletes the attachment contents of a purged task
+func removeAttachmentBlobs(ctx context.Context, blobs repository.BlobStore, logger *zap.Logger, tenantID, taskID string) {
+	if err := blobs.DeletePrefix(ctx, repository.AttachmentPrefix(tenantID, taskID)); err != nil {
+		logger.Warn("failed to delete attachments", zap.String("task_id", taskID), zap.Error(err))
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/tenant"
+)
+
+// Trashing and restoring bump the version, so caches and If-Match notice,
+// but are not edits and keep updated_at
+func TestTrashRoundTripKeepsUpdatedAt(t *testing.T) {
+	s := newTestTaskService(t)
+	ctx := testContext(tenant.Default)
+	task := createTask(t, ctx, s, "trashed")
+	if _, err := s.GetByID(ctx, task.ID); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	if err := s.Delete(ctx, task.ID, task.Version); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	restored, err := s.Restore(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.Version != task.Version+2 || !restored.UpdatedAt.Equal(task.UpdatedAt) {
+		t.Errorf("Restore() version = %d, updated_at = %v; want %d, %v",
+			restored.Version, restored.UpdatedAt, task.Version+2, task.UpdatedAt)
+	}
+
+	got, err := s.GetByID(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetByID() after restore error = %v", err)
+	}
+	if got.Version != restored.Version {
+		t.Errorf("GetByID() version = %d, want %d: the cache was not invalidated", got.Version, restored.Version)
+	}
+}
+
+func TestTrashAndRestore(t *testing.T) {
+	s := newTestTaskService(t)
+	s.subtasks.OnDelete = model.SubtasksCascade
+	ctx := testContext(tenant.Default)
+	parent := createTask(t, ctx, s, "parent")
+	child, err := s.Create(ctx, model.TaskCreateRequest{Title: "child", ParentID: &parent.ID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := s.Restore(ctx, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("Restore() of a live task error = %v, want ErrTaskNotFound", err)
+	}
+	if err := s.Purge(ctx, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("Purge() of a live task error = %v, want ErrTaskNotFound", err)
+	}
+
+	if err := s.Delete(ctx, parent.ID, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	assertTrash(t, ctx, s, parent.ID, child.ID)
+	if _, err := s.GetByID(ctx, child.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Fatalf("GetByID() of a trashed subtask error = %v, want ErrTaskNotFound", err)
+	}
+	if _, err := s.Restore(ctx, child.ID); !errors.Is(err, ErrParentInTrash) {
+		t.Fatalf("Restore() of a subtask in the trash with its parent error = %v, want ErrParentInTrash", err)
+	}
+
+	if _, err := s.Restore(ctx, parent.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	assertTrash(t, ctx, s)
+	if got, err := s.GetByID(ctx, child.ID); err != nil || got.ParentID == nil || *got.ParentID != parent.ID {
+		t.Fatalf("GetByID() of the restored subtask = %+v, %v; want it back under its parent", got, err)
+	}
+
+	if err := s.Delete(ctx, parent.ID, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Purge(ctx, parent.ID); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	assertTrash(t, ctx, s)
+	if _, err := s.Restore(ctx, parent.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Restore() of a purged task error = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestTrashPurgerRetention(t *testing.T) {
+	s := newTestTaskService(t)
+	purger := NewTrashPurger(s.taskStore, s.activityStore, s.blobStore, time.Hour, time.Minute, zap.NewNop())
+	acme, globex := testContext("acme"), testContext("globex")
+
+	kept := createTask(t, acme, s, "kept")
+	var trashed []string
+	for _, ctx := range []context.Context{acme, globex} {
+		task := createTask(t, ctx, s, "trashed")
+		if err := s.Delete(ctx, task.ID, 0); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		trashed = append(trashed, task.ID)
+	}
+
+	purger.PurgeExpired(context.Background(), time.Now())
+	assertTrash(t, acme, s, trashed[0])
+	assertTrash(t, globex, s, trashed[1])
+
+	// Past the retention period the trash of every tenant is emptied
+	purger.PurgeExpired(context.Background(), time.Now().Add(2*time.Hour))
+	assertTrash(t, acme, s)
+	assertTrash(t, globex, s)
+	if _, err := s.GetByID(acme, kept.ID); err != nil {
+		t.Errorf("GetByID() of a live task after purging error = %v", err)
+	}
+
+	logs, err := s.GetActivities(globex, trashed[1], 10)
+	if err != nil {
+		t.Fatalf("GetActivities() error = %v", err)
+	}
+	if len(logs) == 0 || logs[0].Action != "purged" {
+		t.Errorf("newest activity = %+v, want the purge", logs)
+	}
+}
+
+// assertTrash checks that the trash holds exactly ids and that none of them
+// is listed among the live tasks
+func assertTrash(t *testing.T, ctx context.Context, s *TaskService, ids ...string) {
+	t.Helper()
+
+	trash, err := s.ListTrash(ctx, model.TaskListQuery{})
+	if err != nil {
+		t.Fatalf("ListTrash() error = %v", err)
+	}
+	inTrash := map[string]bool{}
+	for _, task := range trash.Data {
+		inTrash[task.ID] = true
+	}
+	if len(trash.Data) != len(ids) {
+		t.Errorf("trash holds %d tasks, want %d", len(trash.Data), len(ids))
+	}
+
+	live, err := s.List(ctx, model.TaskListQuery{})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for _, id := range ids {
+		if !inTrash[id] {
+			t.Errorf("task %s is not in the trash", id)
+		}
+		for _, task := range live.Data {
+			if task.ID == id {
+				t.Errorf("trashed task %s is listed", id)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+	"github.com/hamfa/task-manager/internal/repository"
+)
+
+// UserService handles business logic for users
+type UserService struct {
+	userStore    repository.UserStore
+	projectStore repository.ProjectStore
+	tasks        *TaskService
+	logger       *zap.Logger
+}
+
+// NewUserService creates a new user service
+func NewUserService(users repository.UserStore, projects repository.ProjectStore, tasks *TaskService, logger *zap.Logger) *UserService {
+	return &UserService{
+		userStore:    users,
+		projectStore: projects,
+		tasks:        tasks,
+		logger:       logger,
+	}
+}
+
+// Create creates a new user
+func (s *UserService) Create(ctx context.Context, req model.UserCreateRequest) (*model.User, error) {
+	user, err := s.userStore.Create(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("service: create user: %w", err)
+	}
+	return user, nil
+}
+
+// GetByID retrieves a user by ID
+func (s *UserService) GetByID(ctx context.Context, id string) (*model.User, error) {
+	user, err := s.userStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: get user: %w", err)
+	}
+	return user, nil
+}
+
+// List retrieves one page of users
+func (s *UserService) List(ctx context.Context, page, perPage int) (*model.UserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > model.MaxPerPage {
+		perPage = model.DefaultPerPage
+	}
+
+	users, total, err := s.userStore.List(ctx, page, perPage)
+	if err != nil {
+		return nil, fmt.Errorf("service: list users: %w", err)
+	}
+	if users == nil {
+		users = []model.User{}
+	}
+
+	return &model.UserListResponse{
+		Data:    users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
+}
+
+// Update modifies a user
+func (s *UserService) Update(ctx context.Context, id string, req model.UserUpdateRequest) (*model.User, error) {
+	user, err := s.userStore.Update(ctx, id, req)
+	if err != nil {
+		return nil, fmt.Errorf("service: update user: %w", err)
+	}
+	return user, nil
+}
+
+// Delete removes a user after unassigning them from their tasks and clearing
+// them as project owner
+func (s *UserService) Delete(ctx context.Context, id string) error {
+	if _, err := s.userStore.GetByID(ctx, id); err != nil {
+		return fmt.Errorf("service: delete user: %w", err)
+	}
+
+	if err := s.tasks.ReleaseUser(ctx, id); err != nil {
+		return err
+	}
+	if err := s.projectStore.ReleaseOwner(ctx, id); err != nil {
+		return fmt.Errorf("service: delete user: %w", err)
+	}
+
+	if err := s.userStore.Delete(ctx, id); err != nil {
+		return fmt.Errorf("service: delete user: %w", err)
+	}
+	return nil
+}
+
+// ListTasks retrieves one page of the tasks assigned to a user
+func (s *UserService) ListTasks(ctx context.Context, id string, q model.TaskListQuery) (*model.TaskListResponse, error) {
+	if _, err := s.userStore.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("service: list user tasks: %w", err)
+	}
+
+	q.Filter.AssigneeIDs = []string{id}
+	return s.tasks.List(ctx, q)
+}
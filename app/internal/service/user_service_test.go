@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/hamfa/task-manager/internal/model"
+)
+
+func TestUsersAreScopedToTenant(t *testing.T) {
+	tasks := newTestTaskService(t)
+	s := NewUserService(tasks.userStore, tasks.projectStore, tasks, zap.NewNop())
+	acme, globex := testContext("acme"), testContext("globex")
+
+	req := model.UserCreateRequest{Username: "alice", Email: "alice@example.com"}
+	alice, err := s.Create(acme, req)
+	if err != nil {
+		t.Fatalf("Create() in acme error = %v", err)
+	}
+	// Usernames and emails are unique per tenant only
+	if _, err := s.Create(globex, req); err != nil {
+		t.Fatalf("Create() of the same user in globex error = %v", err)
+	}
+	if _, err := s.Create(acme, req); !errors.Is(err, ErrUserExists) {
+		t.Fatalf("Create() of a duplicate in acme error = %v, want ErrUserExists", err)
+	}
+
+	name := "Mallory"
+	notFound := []struct {
+		name string
+		call func() error
+	}{
+		{"get", func() error { _, err := s.GetByID(globex, alice.ID); return err }},
+		{"update", func() error {
+			_, err := s.Update(globex, alice.ID, model.UserUpdateRequest{DisplayName: &name})
+			return err
+		}},
+		{"delete", func() error { return s.Delete(globex, alice.ID) }},
+		{"list tasks", func() error { _, err := s.ListTasks(globex, alice.ID, model.TaskListQuery{}); return err }},
+	}
+	for _, tt := range notFound {
+		t.Run(tt.name+" from another tenant", func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrUserNotFound) {
+				t.Fatalf("error = %v, want ErrUserNotFound", err)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		resp, err := s.List(globex, 1, 50)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if resp.Total != 1 || resp.Data[0].ID == alice.ID || resp.Data[0].TenantID != "globex" {
+			t.Fatalf("List() in globex = %+v, want only its own user", resp.Data)
+		}
+	})
+
+	t.Run("assign across tenants", func(t *testing.T) {
+		_, err := tasks.Create(globex, model.TaskCreateRequest{Title: "steal", AssigneeID: &alice.ID})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("Create() with another tenant's assignee error = %v, want ErrValidation", err)
+		}
+	})
+
+	t.Run("delete releases only the own tenant", func(t *testing.T) {
+		own, err := tasks.Create(acme, model.TaskCreateRequest{Title: "own", AssigneeID: &alice.ID})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		// A reference from another tenant, as left by data from before users had tenants
+		foreign, err := tasks.taskStore.Create(globex, model.TaskCreateRequest{Title: "foreign", AssigneeID: &alice.ID})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		if err := s.Delete(acme, alice.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if got, _ := tasks.taskStore.GetByID(acme, own.ID); got.AssigneeID != nil {
+			t.Errorf("own task still assigned to %s", *got.AssigneeID)
+		}
+		if got, _ := tasks.taskStore.GetByID(globex, foreign.ID); got.AssigneeID == nil || got.Version != foreign.Version {
+			t.Errorf("task of another tenant was changed: %+v", got)
+		}
+	})
+}
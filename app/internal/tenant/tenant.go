@@ -0,0 +1,43 @@
+// Package tenant carries the workspace a request operates on.
+//
+// Tasks and their activity logs belong to exactly one tenant. Stores read the
+// tenant from the context and refuse to run tenant-scoped operations without
+// one, so a missing scope fails closed instead of leaking data.
+package tenant
+
+import (
+	"context"
+	"regexp"
+)
+
+const (
+	// Header selects the tenant for principals that are not bound to one
+	Header = "X-Tenant-ID"
+	// Default is used when neither the principal nor the request names a tenant
+	Default = "default"
+	// All scopes background jobs that work across tenants, such as reminders.
+	// It is never accepted from a request.
+	All = "*"
+)
+
+// idPattern restricts tenant IDs to short slugs, which keeps them safe in
+// cache keys and Postgres settings
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Valid reports whether id is an acceptable tenant ID
+func Valid(id string) bool {
+	return idPattern.MatchString(id)
+}
+
+type tenantKey struct{}
+
+// WithID returns a copy of ctx scoped to tenant id
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, id)
+}
+
+// FromContext returns the tenant ctx is scoped to, if any
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantKey{}).(string)
+	return id, ok && id != ""
+}
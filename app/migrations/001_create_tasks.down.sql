@@ -0,0 +1,6 @@
+-- 001_create_tasks.down.sql
+-- Drop the tasks table and its trigger function
+
+DROP TRIGGER IF EXISTS update_tasks_updated_at ON tasks;
+DROP FUNCTION IF EXISTS update_updated_at_column();
+DROP TABLE IF EXISTS tasks;
@@ -1,4 +1,4 @@
--- 001_create_tasks.sql
+-- 001_create_tasks.up.sql
 -- Create tasks table for the Task Manager API
 
 CREATE TABLE IF NOT EXISTS tasks (
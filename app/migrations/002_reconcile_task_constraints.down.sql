@@ -0,0 +1,3 @@
+-- 002_reconcile_task_constraints.down.sql
+-- No-op: the constraints belong to the 001 table definition and are dropped with it.
+SELECT 1;
@@ -0,0 +1,17 @@
+-- 002_reconcile_task_constraints.up.sql
+-- Databases created by the old inline InitSchema have no CHECK constraints,
+-- because 001 uses CREATE TABLE IF NOT EXISTS. Add them where missing.
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'tasks_status_check') THEN
+        ALTER TABLE tasks ADD CONSTRAINT tasks_status_check
+            CHECK (status IN ('pending', 'in_progress', 'completed', 'cancelled'));
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'tasks_priority_check') THEN
+        ALTER TABLE tasks ADD CONSTRAINT tasks_priority_check
+            CHECK (priority IN ('low', 'medium', 'high', 'critical'));
+    END IF;
+END
+$$;
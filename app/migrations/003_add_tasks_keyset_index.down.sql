@@ -0,0 +1,3 @@
+-- 003_add_tasks_keyset_index.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
@@ -0,0 +1,4 @@
+-- 003_add_tasks_keyset_index.up.sql
+-- Composite index backing (created_at, id) keyset pagination
+
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at_id ON tasks(created_at DESC, id DESC);
@@ -0,0 +1,5 @@
+-- 004_add_tasks_sort_indexes.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_priority_rank_id;
+DROP INDEX IF EXISTS idx_tasks_title_id;
+DROP INDEX IF EXISTS idx_tasks_updated_at_id;
@@ -0,0 +1,4 @@
+-- 005_add_tasks_search.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
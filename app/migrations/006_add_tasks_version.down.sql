@@ -0,0 +1,3 @@
+-- 006_add_tasks_version.down.sql
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS version;
@@ -0,0 +1,4 @@
+-- 006_add_tasks_version.up.sql
+-- Row version for optimistic concurrency (ETag / If-Match)
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
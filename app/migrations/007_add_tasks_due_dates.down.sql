@@ -0,0 +1,9 @@
+-- 007_add_tasks_due_dates.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_open_due_at;
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_due_after_start;
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS overdue_reminded_for,
+    DROP COLUMN IF EXISTS due_soon_reminded_for,
+    DROP COLUMN IF EXISTS due_at,
+    DROP COLUMN IF EXISTS start_at;
@@ -0,0 +1,17 @@
+-- 007_add_tasks_due_dates.up.sql
+-- Due dates plus the bookkeeping for due_soon / overdue reminders.
+-- *_reminded_for holds the due_at a reminder was sent for, so moving the
+-- deadline re-arms the reminder.
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS start_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS due_soon_reminded_for TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS overdue_reminded_for TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_due_after_start;
+ALTER TABLE tasks ADD CONSTRAINT tasks_due_after_start
+    CHECK (start_at IS NULL OR due_at IS NULL OR due_at >= start_at);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_open_due_at ON tasks(due_at)
+    WHERE due_at IS NOT NULL AND status NOT IN ('completed', 'cancelled');
@@ -0,0 +1,9 @@
+-- 008_create_users.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_reporter_id;
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+ALTER TABLE tasks
+    DROP COLUMN IF EXISTS reporter_id,
+    DROP COLUMN IF EXISTS assignee_id;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,27 @@
+-- 008_create_users.up.sql
+-- Users, and task assignee / reporter references to them
+
+CREATE TABLE IF NOT EXISTS users (
+    id VARCHAR(36) PRIMARY KEY,
+    username VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(lower(email));
+
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS assignee_id VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS reporter_id VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks(assignee_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_reporter_id ON tasks(reporter_id);
@@ -0,0 +1,3 @@
+-- 009_create_api_keys.down.sql
+
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,10 @@
+-- 010_add_tenants.down.sql
+
+DROP POLICY IF EXISTS tasks_tenant_isolation ON tasks;
+ALTER TABLE tasks DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_tasks_tenant_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at_id ON tasks(created_at DESC, id DESC);
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS tenant_id;
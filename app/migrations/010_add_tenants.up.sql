@@ -0,0 +1,23 @@
+-- 010_add_tenants.up.sql
+-- Tenant (workspace) isolation. Existing tasks move to the 'default' tenant;
+-- API keys without a tenant may choose one per request.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+ALTER TABLE tasks ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63);
+
+-- Every task query filters on the tenant, so lead the default keyset index with it
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_created_at_id ON tasks(tenant_id, created_at DESC, id DESC);
+
+-- Row-level security backs up the application's filters. The table owner
+-- bypasses it, so it binds only when the server connects as another role with
+-- POSTGRES_RLS=true, which sets app.tenant_id on every connection. '*' is
+-- used by background jobs that work across tenants.
+ALTER TABLE tasks ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tasks_tenant_isolation ON tasks;
+CREATE POLICY tasks_tenant_isolation ON tasks
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
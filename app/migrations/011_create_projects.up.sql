@@ -0,0 +1,35 @@
+-- 011_create_projects.up.sql
+-- Projects group the tasks of a tenant
+
+CREATE TABLE IF NOT EXISTS projects (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_tenant_name ON projects(tenant_id, lower(name));
+
+DROP TRIGGER IF EXISTS update_projects_updated_at ON projects;
+CREATE TRIGGER update_projects_updated_at
+    BEFORE UPDATE ON projects
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- A project with tasks cannot be deleted
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS project_id VARCHAR(36) REFERENCES projects(id) ON DELETE RESTRICT;
+
+-- Serves both the project_id filter and the summary aggregation
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id, status, priority);
+
+ALTER TABLE projects ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS projects_tenant_isolation ON projects;
+CREATE POLICY projects_tenant_isolation ON projects
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
@@ -0,0 +1,4 @@
+-- 012_create_tags.down.sql
+
+DROP TABLE IF EXISTS task_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,26 @@
+-- 012_create_tags.up.sql
+-- Tags of a tenant and their many-to-many link to tasks
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+-- The primary key serves lookups by task; this one the tag filters and counts
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag_id ON task_tags(tag_id, task_id);
+
+ALTER TABLE tags ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tags_tenant_isolation ON tags;
+CREATE POLICY tags_tenant_isolation ON tags
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
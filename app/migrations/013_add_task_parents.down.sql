@@ -0,0 +1,4 @@
+-- 013_add_task_parents.down.sql
+
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,9 @@
+-- 013_add_task_parents.up.sql
+-- Subtasks point at their parent task. The application decides what deleting
+-- a parent does (SUBTASK_ON_DELETE); the key only keeps references valid.
+
+ALTER TABLE tasks
+    ADD COLUMN IF NOT EXISTS parent_id VARCHAR(36) REFERENCES tasks(id) ON DELETE SET NULL;
+
+-- Serves the recursive subtree walks and the children listing
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id, created_at DESC, id DESC);
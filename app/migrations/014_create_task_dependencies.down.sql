@@ -0,0 +1,3 @@
+-- 014_create_task_dependencies.down.sql
+
+DROP TABLE IF EXISTS task_dependencies;
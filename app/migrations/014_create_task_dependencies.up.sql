@@ -0,0 +1,22 @@
+-- 014_create_task_dependencies.up.sql
+-- task_id is blocked by blocker_id; the application rejects cycles
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocker_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tenant_id VARCHAR(63) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, blocker_id),
+    CHECK (task_id <> blocker_id)
+);
+
+-- The primary key serves lookups of a task's blockers; this one the reverse
+-- walk and the cascade when a blocker is deleted
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocker_id ON task_dependencies(blocker_id);
+
+ALTER TABLE task_dependencies ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS task_dependencies_tenant_isolation ON task_dependencies;
+CREATE POLICY task_dependencies_tenant_isolation ON task_dependencies
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
@@ -0,0 +1,3 @@
+-- 015_create_task_attachments.down.sql
+
+DROP TABLE IF EXISTS task_attachments;
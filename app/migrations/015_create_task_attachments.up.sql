@@ -0,0 +1,25 @@
+-- 015_create_task_attachments.up.sql
+-- Attachment metadata; the contents live in the configured blob store and
+-- are removed by the application when the task is deleted
+
+CREATE TABLE IF NOT EXISTS task_attachments (
+    id VARCHAR(36) PRIMARY KEY,
+    tenant_id VARCHAR(63) NOT NULL,
+    task_id VARCHAR(36) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    checksum CHAR(64) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    uploaded_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_attachments_task_id ON task_attachments(task_id, created_at);
+
+ALTER TABLE task_attachments ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS task_attachments_tenant_isolation ON task_attachments;
+CREATE POLICY task_attachments_tenant_isolation ON task_attachments
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
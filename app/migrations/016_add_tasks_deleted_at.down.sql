@@ -0,0 +1,6 @@
+-- 016_add_tasks_deleted_at.down.sql
+-- Without the column trashed tasks would come back to life, so purge them
+
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,10 @@
+-- 016_add_tasks_deleted_at.up.sql
+-- Deleting a task moves it to the trash. Trashed tasks keep their row, with
+-- deleted_at set, until they are restored or purged; the application leaves
+-- them out of every other query.
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Serves the trash listing and the purger, which looks for expired tasks
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at)
+    WHERE deleted_at IS NOT NULL;
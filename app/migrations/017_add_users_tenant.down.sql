@@ -0,0 +1,12 @@
+-- 017_add_users_tenant.down.sql
+-- Fails if two tenants have users with the same username or email
+
+DROP POLICY IF EXISTS users_tenant_isolation ON users;
+ALTER TABLE users DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_users_tenant_username;
+DROP INDEX IF EXISTS idx_users_tenant_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users(lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users(lower(email));
+
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
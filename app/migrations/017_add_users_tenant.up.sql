@@ -0,0 +1,38 @@
+-- 017_add_users_tenant.up.sql
+-- Users belong to a tenant, like the tasks and projects that reference them.
+-- A user referenced only by the tasks and projects of one tenant joins it;
+-- every other existing user moves to the 'default' tenant.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(63);
+
+UPDATE users u
+SET tenant_id = refs.tenant_id
+FROM (
+    SELECT user_id, MIN(tenant_id) AS tenant_id
+    FROM (
+        SELECT assignee_id AS user_id, tenant_id FROM tasks WHERE assignee_id IS NOT NULL
+        UNION ALL
+        SELECT reporter_id, tenant_id FROM tasks WHERE reporter_id IS NOT NULL
+        UNION ALL
+        SELECT owner_id, tenant_id FROM projects WHERE owner_id IS NOT NULL
+    ) r
+    GROUP BY user_id
+    HAVING COUNT(DISTINCT tenant_id) = 1
+) refs
+WHERE u.id = refs.user_id AND u.tenant_id IS NULL;
+
+UPDATE users SET tenant_id = 'default' WHERE tenant_id IS NULL;
+ALTER TABLE users ALTER COLUMN tenant_id SET NOT NULL;
+
+-- Usernames and emails are unique per tenant
+DROP INDEX IF EXISTS idx_users_username;
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_username ON users(tenant_id, lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_tenant_email ON users(tenant_id, lower(email));
+
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_tenant_isolation ON users;
+CREATE POLICY users_tenant_isolation ON users
+    USING (tenant_id = current_setting('app.tenant_id', true)
+        OR current_setting('app.tenant_id', true) = '*');
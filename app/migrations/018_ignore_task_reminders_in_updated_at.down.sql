@@ -0,0 +1,9 @@
+-- 018_ignore_task_reminders_in_updated_at.down.sql
+
+DROP TRIGGER IF EXISTS update_tasks_updated_at ON tasks;
+CREATE TRIGGER update_tasks_updated_at
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+DROP FUNCTION IF EXISTS update_tasks_updated_at_column();
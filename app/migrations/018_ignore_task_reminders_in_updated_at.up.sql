@@ -0,0 +1,24 @@
+-- 018_ignore_task_reminders_in_updated_at.up.sql
+-- Claiming a reminder only records the due date it was sent for. That is
+-- bookkeeping, not an edit: it must not move updated_at, which would then
+-- disagree with version and with the memory backend.
+
+CREATE OR REPLACE FUNCTION update_tasks_updated_at_column()
+RETURNS TRIGGER AS $$
+DECLARE
+    -- search_vector is generated and not yet computed for NEW
+    ignored TEXT[] := ARRAY['updated_at', 'search_vector', 'due_soon_reminded_for', 'overdue_reminded_for'];
+BEGIN
+    IF (to_jsonb(NEW) - ignored) = (to_jsonb(OLD) - ignored) THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS update_tasks_updated_at ON tasks;
+CREATE TRIGGER update_tasks_updated_at
+    BEFORE UPDATE ON tasks
+    FOR EACH ROW
+    EXECUTE FUNCTION update_tasks_updated_at_column();
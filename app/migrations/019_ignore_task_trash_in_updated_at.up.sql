@@ -0,0 +1,19 @@
+-- 019_ignore_task_trash_in_updated_at.up.sql
+-- Moving a task to or from the trash bumps its version but is not an edit,
+-- so like claiming a reminder (018) it leaves updated_at alone. Statements
+-- that edit a task set updated_at themselves.
+
+CREATE OR REPLACE FUNCTION update_tasks_updated_at_column()
+RETURNS TRIGGER AS $$
+DECLARE
+    -- search_vector is generated and not yet computed for NEW
+    ignored TEXT[] := ARRAY['updated_at', 'search_vector', 'due_soon_reminded_for', 'overdue_reminded_for',
+        'deleted_at', 'version'];
+BEGIN
+    IF (to_jsonb(NEW) - ignored) = (to_jsonb(OLD) - ignored) THEN
+        RETURN NEW;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
@@ -0,0 +1,13 @@
+-- 020_add_tenant_to_tasks_sort_indexes.up.sql
+-- Lead the sort indexes from 004 with the tenant, like the default keyset
+-- index since 010, so every sort order pages within one tenant
+
+DROP INDEX IF EXISTS idx_tasks_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_title_id;
+DROP INDEX IF EXISTS idx_tasks_priority_rank_id;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_updated_at_id ON tasks(tenant_id, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_title_id ON tasks(tenant_id, title, id);
+CREATE INDEX IF NOT EXISTS idx_tasks_tenant_priority_rank_id ON tasks(tenant_id, (
+    CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 WHEN 'critical' THEN 4 ELSE 0 END
+), id);
@@ -0,0 +1,12 @@
+// Package migrations embeds the versioned SQL migration files.
+//
+// Files are named NNN_description.up.sql / NNN_description.down.sql and are
+// applied in version order by internal/migrate.
+package migrations
+
+import "embed"
+
+// FS holds every *.sql file in this directory
+//
+//go:embed *.sql
+var FS embed.FS
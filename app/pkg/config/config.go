@@ -2,16 +2,89 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
+// Storage backends selectable through STORAGE_BACKEND
+const (
+	// StorageDatabase uses PostgreSQL, MongoDB and Redis
+	StorageDatabase = "database"
+	// StorageMemory keeps all data in process memory (demos and tests)
+	StorageMemory = "memory"
+)
+
+// Blob stores selectable through ATTACHMENT_STORE
+const (
+	// BlobStoreLocal keeps attachment contents in a local directory
+	BlobStoreLocal = "local"
+	// BlobStoreS3 keeps attachment contents in an S3-compatible bucket
+	BlobStoreS3 = "s3"
+)
+
 // Config holds all application configuration
 type Config struct {
 	// Server
-	ServerPort string `envconfig:"SERVER_PORT" default:"8080"`
+	ServerPort  string `envconfig:"SERVER_PORT" default:"8080"`
 	Environment string `envconfig:"ENVIRONMENT" default:"development"`
 
+	// HTTP timeouts; attachment uploads and downloads get
+	// AttachmentTransferTimeout instead of the read and write timeouts
+	ServerReadTimeout  time.Duration `envconfig:"SERVER_READ_TIMEOUT" default:"15s"`
+	ServerWriteTimeout time.Duration `envconfig:"SERVER_WRITE_TIMEOUT" default:"15s"`
+	ServerIdleTimeout  time.Duration `envconfig:"SERVER_IDLE_TIMEOUT" default:"60s"`
+
+	// Authentication; when disabled the /api routes are anonymous
+	AuthEnabled         bool          `envconfig:"AUTH_ENABLED" default:"true"`
+	JWTIssuer           string        `envconfig:"JWT_ISSUER"`
+	JWTAudience         string        `envconfig:"JWT_AUDIENCE"`
+	JWTHS256Secret      string        `envconfig:"JWT_HS256_SECRET"`
+	JWTRSAPublicKeyFile string        `envconfig:"JWT_RSA_PUBLIC_KEY_FILE"`
+	JWTJWKSFile         string        `envconfig:"JWT_JWKS_FILE"`
+	JWTLeeway           time.Duration `envconfig:"JWT_LEEWAY" default:"30s"`
+
+	// Tenancy: requests that name no tenant use DefaultTenant. PostgresRLS
+	// sets app.tenant_id on every connection for the row-level security policies.
+	DefaultTenant string `envconfig:"DEFAULT_TENANT" default:"default"`
+	PostgresRLS   bool   `envconfig:"POSTGRES_RLS" default:"false"`
+
+	// Status workflow, e.g. "pending:in_progress|cancelled,in_progress:completed";
+	// empty uses the built-in workflow
+	TaskWorkflow map[string]string `envconfig:"TASK_WORKFLOW"`
+
+	// Subtasks: whether a parent may be completed while subtasks are open, and
+	// what deleting a parent does to its subtasks (reject, cascade or orphan)
+	SubtaskCompleteWithOpen bool   `envconfig:"SUBTASK_COMPLETE_WITH_OPEN" default:"false"`
+	SubtaskOnDelete         string `envconfig:"SUBTASK_ON_DELETE" default:"reject"`
+
+	// Due date reminders
+	RemindersEnabled bool          `envconfig:"REMINDERS_ENABLED" default:"true"`
+	ReminderInterval time.Duration `envconfig:"REMINDER_INTERVAL" default:"1m"`
+	DueSoonWindow    time.Duration `envconfig:"REMINDER_DUE_SOON_WINDOW" default:"24h"`
+
+	// Trash: deleted tasks are purged TrashRetention after deletion, checked
+	// every TrashPurgeInterval; a retention of 0 keeps them until purged by hand
+	TrashRetention     time.Duration `envconfig:"TRASH_RETENTION" default:"720h"`
+	TrashPurgeInterval time.Duration `envconfig:"TRASH_PURGE_INTERVAL" default:"1h"`
+
+	// Storage
+	StorageBackend string `envconfig:"STORAGE_BACKEND" default:"database"`
+
+	// Attachments: contents go to a local directory or an S3-compatible
+	// bucket such as MinIO; uploads are limited to AttachmentMaxBytes
+	AttachmentStore    string `envconfig:"ATTACHMENT_STORE" default:"local"`
+	AttachmentDir      string `envconfig:"ATTACHMENT_DIR" default:"data/attachments"`
+	AttachmentMaxBytes int64  `envconfig:"ATTACHMENT_MAX_BYTES" default:"26214400"`
+	S3Endpoint         string `envconfig:"S3_ENDPOINT" default:"localhost:9000"`
+	S3Region           string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3Bucket           string `envconfig:"S3_BUCKET" default:"task-attachments"`
+	S3AccessKey        string `envconfig:"S3_ACCESS_KEY"`
+	S3SecretKey        string `envconfig:"S3_SECRET_KEY"`
+	S3UseSSL           bool   `envconfig:"S3_USE_SSL" default:"false"`
+
+	AttachmentTransferTimeout time.Duration `envconfig:"ATTACHMENT_TRANSFER_TIMEOUT" default:"10m"`
+
 	// PostgreSQL
 	PostgresHost     string `envconfig:"POSTGRES_HOST" default:"localhost"`
 	PostgresPort     string `envconfig:"POSTGRES_PORT" default:"5432"`
@@ -19,6 +92,7 @@ type Config struct {
 	PostgresPassword string `envconfig:"POSTGRES_PASSWORD" default:"hamfa_secret"`
 	PostgresDB       string `envconfig:"POSTGRES_DB" default:"taskmanager"`
 	PostgresSSLMode  string `envconfig:"POSTGRES_SSL_MODE" default:"disable"`
+	MigrateOnStart   bool   `envconfig:"MIGRATE_ON_START" default:"true"`
 
 	// MongoDB
 	MongoURI string `envconfig:"MONGO_URI" default:"mongodb://localhost:27017"`
@@ -37,9 +111,54 @@ func Load() (*Config, error) {
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+
+	switch cfg.StorageBackend {
+	case StorageDatabase, StorageMemory:
+	default:
+		return nil, fmt.Errorf("invalid STORAGE_BACKEND %q: must be %q or %q",
+			cfg.StorageBackend, StorageDatabase, StorageMemory)
+	}
+
+	// The memory API key store starts empty, so only a JWT key can let anyone in
+	if cfg.AuthEnabled && cfg.StorageBackend == StorageMemory && !cfg.JWTConfigured() {
+		return nil, fmt.Errorf("AUTH_ENABLED with STORAGE_BACKEND=%q requires JWT_HS256_SECRET, "+
+			"JWT_RSA_PUBLIC_KEY_FILE or JWT_JWKS_FILE; set AUTH_ENABLED=false to run without authentication",
+			StorageMemory)
+	}
+
+	switch cfg.AttachmentStore {
+	case BlobStoreLocal, BlobStoreS3:
+	default:
+		return nil, fmt.Errorf("invalid ATTACHMENT_STORE %q: must be %q or %q",
+			cfg.AttachmentStore, BlobStoreLocal, BlobStoreS3)
+	}
+	if cfg.AttachmentMaxBytes <= 0 {
+		return nil, fmt.Errorf("invalid ATTACHMENT_MAX_BYTES %d: must be positive", cfg.AttachmentMaxBytes)
+	}
+	for name, d := range map[string]time.Duration{
+		"SERVER_READ_TIMEOUT":         cfg.ServerReadTimeout,
+		"SERVER_WRITE_TIMEOUT":        cfg.ServerWriteTimeout,
+		"ATTACHMENT_TRANSFER_TIMEOUT": cfg.AttachmentTransferTimeout,
+	} {
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid %s %s: must be positive", name, d)
+		}
+	}
+	if cfg.TrashRetention < 0 {
+		return nil, fmt.Errorf("invalid TRASH_RETENTION %s: must not be negative", cfg.TrashRetention)
+	}
+	if cfg.TrashPurgeInterval <= 0 {
+		return nil, fmt.Errorf("invalid TRASH_PURGE_INTERVAL %s: must be positive", cfg.TrashPurgeInterval)
+	}
+
 	return &cfg, nil
 }
 
+// JWTConfigured reports whether any JWT verification key is configured
+func (c *Config) JWTConfigured() bool {
+	return c.JWTHS256Secret != "" || c.JWTRSAPublicKeyFile != "" || c.JWTJWKSFile != ""
+}
+
 // PostgresDSN returns the PostgreSQL connection string
 func (c *Config) PostgresDSN() string {
 	return fmt.Sprintf(
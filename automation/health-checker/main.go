@@ -26,6 +26,9 @@ type Endpoint struct {
 	Method  string `yaml:"method"`
 	Timeout int    `yaml:"timeout_seconds"`
 	Expect  int    `yaml:"expected_status"`
+	// Headers are sent with the request; values may reference environment
+	// variables, e.g. "ApiKey ${TASK_MANAGER_API_KEY}"
+	Headers map[string]string `yaml:"headers"`
 }
 
 // Webhook holds notification configuration
@@ -142,6 +145,9 @@ func checkEndpoint(ep Endpoint) CheckResult {
 		result.Error = err.Error()
 		return result
 	}
+	for name, value := range ep.Headers {
+		req.Header.Set(name, os.ExpandEnv(value))
+	}
 
 	resp, err := client.Do(req)
 	result.Latency = time.Since(start)